@@ -0,0 +1,137 @@
+package settings
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ByteSize is a number of bytes that may be written with a human readable
+// unit such as "10MB", "512KiB", or "1.5G". Units without an "i" are powers
+// of 1000 and units with an "i" are powers of 1024. Units are case
+// insensitive and the trailing "B" is optional. A plain integer is a number
+// of bytes.
+type ByteSize uint64
+
+// Common sizes for use as defaults.
+const (
+	Byte     ByteSize = 1
+	Kilobyte ByteSize = 1000 * Byte
+	Megabyte ByteSize = 1000 * Kilobyte
+	Gigabyte ByteSize = 1000 * Megabyte
+	Terabyte ByteSize = 1000 * Gigabyte
+	Petabyte ByteSize = 1000 * Terabyte
+	Kibibyte ByteSize = 1024 * Byte
+	Mebibyte ByteSize = 1024 * Kibibyte
+	Gibibyte ByteSize = 1024 * Mebibyte
+	Tebibyte ByteSize = 1024 * Gibibyte
+	Pebibyte ByteSize = 1024 * Tebibyte
+)
+
+// byteUnits are ordered from largest to smallest so that the first unit
+// dividing a size exactly is the most readable one.
+var byteUnits = []struct {
+	name string
+	size ByteSize
+}{
+	{"PiB", Pebibyte},
+	{"PB", Petabyte},
+	{"TiB", Tebibyte},
+	{"TB", Terabyte},
+	{"GiB", Gibibyte},
+	{"GB", Gigabyte},
+	{"MiB", Mebibyte},
+	{"MB", Megabyte},
+	{"KiB", Kibibyte},
+	{"KB", Kilobyte},
+}
+
+// ParseByteSize converts a string such as "10MB" into a ByteSize.
+func ParseByteSize(s string) (ByteSize, error) {
+	str := strings.TrimSpace(s)
+	split := strings.IndexFunc(str, func(r rune) bool {
+		return !unicode.IsDigit(r) && r != '.'
+	})
+	if split < 0 {
+		split = len(str)
+	}
+	number, unit := str[:split], strings.TrimSpace(str[split:])
+	if number == "" {
+		return 0, fmt.Errorf("byte size %q has no number", s)
+	}
+	multiplier := Byte
+	if unit != "" {
+		u := strings.ToUpper(unit)
+		if u != "B" && !strings.HasSuffix(u, "B") {
+			u = u + "B"
+		}
+		found := u == "B"
+		for _, bu := range byteUnits {
+			if u == strings.ToUpper(bu.name) {
+				multiplier = bu.size
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("byte size %q has unknown unit %s", s, unit)
+		}
+	}
+	if !strings.Contains(number, ".") {
+		n, err := strconv.ParseUint(number, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("byte size %q is not valid: %s", s, err.Error())
+		}
+		if n > math.MaxUint64/uint64(multiplier) {
+			return 0, fmt.Errorf("byte size %q is too large", s)
+		}
+		return ByteSize(n) * multiplier, nil
+	}
+	// Decimals are scaled to whole numbers so that sizes such as "0.067GB"
+	// are exact rather than subject to floating point rounding.
+	whole, fraction, _ := strings.Cut(number, ".")
+	digits, ok := new(big.Int).SetString(whole+fraction, 10)
+	if !ok || strings.Contains(fraction, ".") {
+		return 0, fmt.Errorf("byte size %q is not valid", s)
+	}
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(len(fraction))), nil)
+	size, remainder := new(big.Int).QuoRem(
+		digits.Mul(digits, new(big.Int).SetUint64(uint64(multiplier))), scale, new(big.Int),
+	)
+	if remainder.Sign() != 0 {
+		return 0, fmt.Errorf("byte size %q is not a whole number of bytes", s)
+	}
+	if !size.IsUint64() {
+		return 0, fmt.Errorf("byte size %q is too large", s)
+	}
+	return ByteSize(size.Uint64()), nil
+}
+
+// String renders the size using the largest unit that represents it
+// exactly so that the output parses back into the same size.
+func (b ByteSize) String() string {
+	for _, bu := range byteUnits {
+		if b >= bu.size && b%bu.size == 0 {
+			return strconv.FormatUint(uint64(b/bu.size), 10) + bu.name
+		}
+	}
+	return strconv.FormatUint(uint64(b), 10) + "B"
+}
+
+// MarshalText renders the size in human readable units.
+func (b ByteSize) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+// UnmarshalText parses a size written with or without units.
+func (b *ByteSize) UnmarshalText(text []byte) error {
+	size, err := ParseByteSize(string(text))
+	if err != nil {
+		return err
+	}
+	*b = size
+	return nil
+}
@@ -0,0 +1,91 @@
+package settings
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       string
+		want    ByteSize
+		wantErr bool
+	}{
+		{name: "plain", v: "1024", want: 1024},
+		{name: "bytes", v: "12B", want: 12},
+		{name: "decimal", v: "10MB", want: 10 * Megabyte},
+		{name: "binary", v: "512KiB", want: 512 * Kibibyte},
+		{name: "short unit", v: "2Ki", want: 2 * Kibibyte},
+		{name: "fraction", v: "1.5G", want: 1500 * Megabyte},
+		{name: "lower case", v: "3gib", want: 3 * Gibibyte},
+		{name: "spaced", v: " 4 TB ", want: 4 * Terabyte},
+		{name: "empty", v: "", wantErr: true},
+		{name: "no number", v: "MB", wantErr: true},
+		{name: "unknown unit", v: "10XB", wantErr: true},
+		{name: "negative", v: "-1MB", wantErr: true},
+		{name: "partial byte", v: "1.5B", wantErr: true},
+		{name: "overflow", v: "20000000PiB", wantErr: true},
+		{name: "exact fraction", v: "0.067GB", want: 67 * Megabyte},
+		{name: "small fraction", v: "0.017TB", want: 17 * Gigabyte},
+		{name: "long fraction", v: "1.00000000000000000000KB", want: Kilobyte},
+		{name: "leading point", v: ".5KB", want: 500},
+		{name: "two points", v: "1..5MB", wantErr: true},
+		{name: "point only", v: ".KB", wantErr: true},
+		{name: "fraction overflow", v: "16384.5PiB", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseByteSize(tt.v)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseByteSize() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseByteSize() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseByteSize_fractions(t *testing.T) {
+	units := []struct {
+		name string
+		size ByteSize
+	}{{"KB", Kilobyte}, {"MB", Megabyte}, {"GB", Gigabyte}, {"TB", Terabyte}, {"PB", Petabyte}}
+	for x, u := range units[1:] {
+		smaller := units[x].size
+		for n := ByteSize(1); n < 1000; n = n + 1 {
+			v := fmt.Sprintf("0.%03d%s", n, u.name)
+			got, err := ParseByteSize(v)
+			if err != nil || got != n*smaller {
+				t.Errorf("ParseByteSize(%s) = %d, %v, want %d", v, got, err, n*smaller)
+			}
+		}
+	}
+}
+
+func TestByteSize_String(t *testing.T) {
+	tests := []struct {
+		v    ByteSize
+		want string
+	}{
+		{v: 0, want: "0B"},
+		{v: 1536, want: "1536B"},
+		{v: 10 * Megabyte, want: "10MB"},
+		{v: 512 * Kibibyte, want: "512KiB"},
+		{v: 1500 * Megabyte, want: "1500MB"},
+		{v: 2 * Pebibyte, want: "2PiB"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.v.String(); got != tt.want {
+				t.Errorf("String() = %s, want %s", got, tt.want)
+			}
+			back, err := ParseByteSize(tt.want)
+			if err != nil || back != tt.v {
+				t.Errorf("ParseByteSize(%s) = %d, %v, want %d", tt.want, back, err, tt.v)
+			}
+		})
+	}
+}
@@ -0,0 +1,291 @@
+package settings
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const cliUsage = `usage: %s <command> [flags]
+
+commands:
+  example   print an example configuration
+  schema    print a JSON Schema that describes the configuration
+  validate  check a configuration without applying it
+  dump      print the effective configuration
+  explain   print where the value of each setting came from
+
+Run "%s <command> -h" for the flags of a command.
+`
+
+// errUsage is returned when the command line cannot be understood. The
+// usage text is printed before it is returned.
+var errUsage = errors.New("invalid usage")
+
+// CLI is a command line interface for a set of groups. Applications create
+// one with the groups of their configuration, usually from Convert or
+// GroupFromComponent, and call Run from a main function:
+//
+//	func main() {
+//	    g, _ := settings.GroupFromComponent(&Component{})
+//	    cli := &settings.CLI{Name: "myapp-config", Groups: []settings.Group{g}}
+//	    if err := cli.Run(context.Background(), os.Args[1:]); err != nil {
+//	        os.Exit(1)
+//	    }
+//	}
+type CLI struct {
+	// Name is the name of the program displayed in usage text.
+	Name   string
+	Groups []Group
+	// Env is the environment used by the -env flag. It defaults to the
+	// environment of the process.
+	Env []string
+	// Stdout and Stderr default to those of the process.
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+func (c *CLI) name() string {
+	if c.Name == "" {
+		return "settings"
+	}
+	return c.Name
+}
+
+func (c *CLI) stdout() io.Writer {
+	if c.Stdout == nil {
+		return os.Stdout
+	}
+	return c.Stdout
+}
+
+func (c *CLI) stderr() io.Writer {
+	if c.Stderr == nil {
+		return os.Stderr
+	}
+	return c.Stderr
+}
+
+func (c *CLI) env() []string {
+	if c.Env == nil {
+		return os.Environ()
+	}
+	return c.Env
+}
+
+// Run executes the command given in args, which should not include the
+// name of the program. Problems found by the validate command are printed
+// to Stdout and any other error is printed to Stderr. A non-nil error means
+// the program should exit with a failure status.
+func (c *CLI) Run(ctx context.Context, args []string) error {
+	if len(args) < 1 {
+		_, _ = fmt.Fprintf(c.stderr(), cliUsage, c.name(), c.name())
+		return errUsage
+	}
+	var err error
+	switch args[0] {
+	case "example":
+		err = c.example(args[1:])
+	case "schema":
+		err = c.schema(args[1:])
+	case "validate":
+		err = c.validate(ctx, args[1:])
+	case "dump":
+		err = c.dump(ctx, args[1:])
+	case "explain":
+		err = c.explain(ctx, args[1:])
+	case "help", "-h", "-help", "--help":
+		_, _ = fmt.Fprintf(c.stdout(), cliUsage, c.name(), c.name())
+		return nil
+	default:
+		_, _ = fmt.Fprintf(c.stderr(), "unknown command %s\n\n", args[0])
+		_, _ = fmt.Fprintf(c.stderr(), cliUsage, c.name(), c.name())
+		return errUsage
+	}
+	var ve *ValidationError
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, flag.ErrHelp):
+		return nil
+	case errors.Is(err, errUsage), errors.As(err, &ve):
+		// The details have already been printed.
+		return err
+	default:
+		_, _ = fmt.Fprintf(c.stderr(), "%s\n", err.Error())
+		return err
+	}
+}
+
+func (c *CLI) flagSet(command string) *flag.FlagSet {
+	fs := flag.NewFlagSet(c.name()+" "+command, flag.ContinueOnError)
+	fs.SetOutput(c.stderr())
+	return fs
+}
+
+// cliSources holds the flags shared by commands that read configuration.
+type cliSources struct {
+	file string
+	env  bool
+}
+
+func (s *cliSources) register(fs *flag.FlagSet) {
+	fs.StringVar(&s.file, "file", "", "path to a YAML or JSON configuration file")
+	fs.BoolVar(&s.env, "env", false, "read configuration from the environment")
+}
+
+// source builds the source described by the flags. Values from the
+// environment take precedence over values from the file. The file source
+// is also returned on its own, or nil if there is no file.
+func (c *CLI) source(s *cliSources) (Source, *MapSource, error) {
+	if s.file == "" && !s.env {
+		return nil, nil, fmt.Errorf("no configuration given. use -file, -env, or both")
+	}
+	var sources MultiSource
+	if s.env {
+		env, err := NewEnvSource(c.env())
+		if err != nil {
+			return nil, nil, err
+		}
+		sources = append(sources, &NamedSource{NameValue: "env", Source: env})
+	}
+	var file *MapSource
+	if s.file != "" {
+		fs, err := NewFileSource(s.file)
+		if err != nil {
+			return nil, nil, err
+		}
+		file = fs
+		sources = append(sources, &NamedSource{NameValue: s.file, Source: fs})
+	}
+	if len(sources) == 1 {
+		return sources[0], file, nil
+	}
+	return sources, file, nil
+}
+
+func (c *CLI) example(args []string) error {
+	fs := c.flagSet("example")
+	format := fs.String("format", "yaml", "output format: yaml, env, or json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	switch *format {
+	case "yaml":
+		_, _ = io.WriteString(c.stdout(), ExampleYamlGroups(c.Groups))
+	case "env":
+		_, _ = io.WriteString(c.stdout(), ExampleEnvGroups(c.Groups))
+	case "json":
+		out, err := ExampleJSONGroups(c.Groups, WithJSONComments())
+		if err != nil {
+			return err
+		}
+		_, _ = io.WriteString(c.stdout(), out)
+	default:
+		return fmt.Errorf("unknown format %s", *format)
+	}
+	return nil
+}
+
+func (c *CLI) schema(args []string) error {
+	if err := c.flagSet("schema").Parse(args); err != nil {
+		return err
+	}
+	out, err := JSONSchemaGroups(c.Groups)
+	if err != nil {
+		return err
+	}
+	_, _ = io.WriteString(c.stdout(), out)
+	return nil
+}
+
+func (c *CLI) validate(ctx context.Context, args []string) error {
+	fs := c.flagSet("validate")
+	var sources cliSources
+	sources.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	s, file, err := c.source(&sources)
+	if err != nil {
+		return err
+	}
+	// Unknown keys are only checked in the file because the environment
+	// contains many unrelated variables.
+	var strict []*MapSource
+	if file != nil {
+		strict = append(strict, file)
+	}
+	err = ValidateSource(ctx, s, c.Groups, strict...)
+	var ve *ValidationError
+	if err != nil && !errors.As(err, &ve) {
+		return err
+	}
+	if ve == nil {
+		_, _ = fmt.Fprintln(c.stdout(), "configuration is valid")
+		return nil
+	}
+	for _, p := range ve.Problems {
+		_, _ = fmt.Fprintln(c.stdout(), p.String())
+	}
+	return ve
+}
+
+func (c *CLI) dump(ctx context.Context, args []string) error {
+	fs := c.flagSet("dump")
+	var sources cliSources
+	sources.register(fs)
+	format := fs.String("format", "yaml", "output format: yaml, env, or json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	s, _, err := c.source(&sources)
+	if err != nil {
+		return err
+	}
+	if err := LoadGroups(ctx, s, c.Groups); err != nil {
+		return err
+	}
+	var out string
+	switch *format {
+	case "yaml":
+		out, err = DumpYamlGroups(c.Groups)
+	case "json":
+		out, err = DumpJSONGroups(c.Groups)
+	case "env":
+		out = DumpEnvGroups(c.Groups)
+	default:
+		err = fmt.Errorf("unknown format %s", *format)
+	}
+	if err != nil {
+		return err
+	}
+	_, _ = io.WriteString(c.stdout(), out)
+	return nil
+}
+
+func (c *CLI) explain(ctx context.Context, args []string) error {
+	fs := c.flagSet("explain")
+	var sources cliSources
+	sources.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	s, _, err := c.source(&sources)
+	if err != nil {
+		return err
+	}
+	provenance, err := ExplainGroups(ctx, s, c.Groups)
+	lines := make([]string, 0, len(provenance))
+	for _, p := range provenance {
+		lines = append(lines, p.String())
+	}
+	if len(lines) > 0 {
+		_, _ = fmt.Fprintln(c.stdout(), strings.Join(lines, "\n"))
+	}
+	return err
+}
@@ -0,0 +1,241 @@
+package settings
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andreyvit/diff"
+)
+
+type cliConf struct {
+	Host     string `required:"true"`
+	Port     int
+	Timeout  time.Duration
+	Password Secret
+}
+
+func newTestCLI(t *testing.T, env ...string) (*CLI, *bytes.Buffer, *bytes.Buffer) {
+	g, err := Convert(&cliConf{Host: "localhost", Port: 80, Timeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr bytes.Buffer
+	return &CLI{
+		Name:   "app",
+		Groups: []Group{g},
+		Env:    append([]string{}, env...),
+		Stdout: &stdout,
+		Stderr: &stderr,
+	}, &stdout, &stderr
+}
+
+func writeCLIFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCLI(t *testing.T) {
+	file := writeCLIFile(t, `cliconf:
+  host: example.com
+  password: hunter2
+`)
+	tests := []struct {
+		name    string
+		env     []string
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "example yaml",
+			args: []string{"example"},
+			want: `cliConf:
+  # (settings.Secret) 
+  password: ""
+  # (time.Duration) 
+  timeout: "1s"
+  # (int) 
+  port: 80
+  # (string) 
+  host: "localhost"
+`,
+		},
+		{
+			name: "example env",
+			args: []string{"example", "-format", "env"},
+			want: `# (settings.Secret) 
+CLICONF_PASSWORD=""
+# (time.Duration) 
+CLICONF_TIMEOUT="1s"
+# (int) 
+CLICONF_PORT="80"
+# (string) 
+CLICONF_HOST="localhost"
+`,
+		},
+		{
+			name: "example json",
+			args: []string{"example", "-format", "json"},
+			want: `{
+  "cliconf": {
+    "$comment": [
+      "password (settings.Secret)",
+      "timeout (time.Duration)",
+      "port (int)",
+      "host (string)"
+    ],
+    "host": "localhost",
+    "password": "",
+    "port": 80,
+    "timeout": "1s"
+  }
+}
+`,
+		},
+		{
+			name: "validate valid",
+			args: []string{"validate", "-file", file},
+			want: "configuration is valid\n",
+		},
+		{
+			name:    "validate invalid",
+			env:     []string{"CLICONF_PORT=http", "CLICONF_HOST=example.com"},
+			args:    []string{"validate", "-env"},
+			want:    "cliConf.Port: unable to cast \"http\" of type string to int64\n",
+			wantErr: true,
+		},
+		{
+			name: "validate env ignores unrelated variables",
+			env:  []string{"HOME=/root", "PATH=/bin", "CLICONF_HOST=example.com"},
+			args: []string{"validate", "-env"},
+			want: "configuration is valid\n",
+		},
+		{
+			name:    "validate unknown key in file",
+			env:     []string{"HOME=/root"},
+			args:    []string{"validate", "-env", "-file", writeCLIFile(t, "cliconf:\n  hots: example.com\n")},
+			want:    "cliConf.Host: a value is required\ncliconf.hots: unknown key (did you mean cliconf.host?)\n",
+			wantErr: true,
+		},
+		{
+			name:    "validate missing required",
+			env:     []string{"CLICONF_PORT=8080"},
+			args:    []string{"validate", "-env"},
+			want:    "cliConf.Host: a value is required\n",
+			wantErr: true,
+		},
+		{
+			name: "dump env over file",
+			env:  []string{"CLICONF_PORT=8080"},
+			args: []string{"dump", "-env", "-file", file, "-format", "json"},
+			want: `{
+  "cliconf": {
+    "host": "example.com",
+    "password": "<redacted>",
+    "port": 8080,
+    "timeout": "1s"
+  }
+}
+`,
+		},
+		{
+			name: "explain",
+			env:  []string{"CLICONF_PORT=8080"},
+			args: []string{"explain", "-env", "-file", file},
+			want: `cliConf.Password = <redacted> (from ` + file + `, raw "<redacted>")
+cliConf.Timeout = 1s (default)
+cliConf.Port = 8080 (from env, raw "8080")
+cliConf.Host = example.com (from ` + file + `, raw "example.com")
+`,
+		},
+		{
+			name:    "no source",
+			args:    []string{"dump"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown command",
+			args:    []string{"apply"},
+			wantErr: true,
+		},
+		{
+			name:    "no command",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli, stdout, _ := newTestCLI(t, tt.env...)
+			err := cli.Run(context.Background(), tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CLI.Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := stdout.String(); got != tt.want {
+				t.Errorf("CLI.Run() = %v, want %v\n%s", got, tt.want, diff.LineDiff(got, tt.want))
+			}
+		})
+	}
+}
+
+func TestCLI_validateUnknownKeys(t *testing.T) {
+	file := writeCLIFile(t, `cliconf:
+  host: example.com
+  prot: 8080
+`)
+	cli, stdout, _ := newTestCLI(t, "HOME=/root")
+	err := cli.Run(context.Background(), []string{"validate", "-env", "-file", file})
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("CLI.Run() error = %v, want *ValidationError", err)
+	}
+	want := "cliconf.prot: unknown key (did you mean cliconf.port?)\n"
+	if got := stdout.String(); got != want {
+		t.Errorf("CLI.Run() = %v, want %v", got, want)
+	}
+}
+
+func TestCLI_errors(t *testing.T) {
+	cli, _, stderr := newTestCLI(t)
+	if err := cli.Run(context.Background(), []string{"example", "-format", "toml"}); err == nil {
+		t.Fatal("CLI.Run() expected an error for an unknown format")
+	}
+	if got := stderr.String(); got != "unknown format toml\n" {
+		t.Errorf("CLI.Run() stderr = %q", got)
+	}
+	cli, _, stderr = newTestCLI(t)
+	if err := cli.Run(context.Background(), []string{"deploy"}); err == nil {
+		t.Fatal("CLI.Run() expected an error for an unknown command")
+	}
+	if !strings.HasPrefix(stderr.String(), "unknown command deploy\n\nusage: app <command> [flags]") {
+		t.Errorf("CLI.Run() stderr = %q", stderr.String())
+	}
+}
+
+type cliSecretConf struct {
+	Token string `secret:"true"`
+}
+
+func TestCLI_explainSecret(t *testing.T) {
+	g, err := Convert(&cliSecretConf{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stdout bytes.Buffer
+	cli := &CLI{Name: "app", Groups: []Group{g}, Env: []string{"CLISECRETCONF_TOKEN=hunter2"}, Stdout: &stdout}
+	if err := cli.Run(context.Background(), []string{"explain", "-env"}); err != nil {
+		t.Fatal(err)
+	}
+	want := "cliSecretConf.Token = <redacted> (from env, raw \"<redacted>\")\n"
+	if got := stdout.String(); got != want {
+		t.Errorf("CLI.Run() = %q, want %q", got, want)
+	}
+}
@@ -2,8 +2,10 @@ package settings
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 )
 
 // NewComponent is the entry point for the high-level api. This method manages much
@@ -44,7 +46,10 @@ import (
 // points to the output of the Component.New method. The method returns an error any
 // time the given component does not satisfy the contract, any time the configuration
 // loading fails, or if the Component.New returns an error.
-func NewComponent(ctx context.Context, s Source, v interface{}, destination interface{}) error {
+//
+// Options, such as WithPath, change where the configuration of the component is
+// found in the source.
+func NewComponent(ctx context.Context, s Source, v interface{}, destination interface{}, opts ...ComponentOption) error {
 	dv := reflect.ValueOf(destination)
 	if dv.Kind() != reflect.Ptr {
 		// The destination needs to be a pointer value in order for us to
@@ -60,30 +65,25 @@ func NewComponent(ctx context.Context, s Source, v interface{}, destination inte
 	}
 	vv := reflect.ValueOf(v)
 	sm := vv.MethodByName("Settings")
-	nm := vv.MethodByName("New")
 	smOut := sm.Call(nil)[0]
 
 	g, err := Convert(smOut.Interface())
 	if err != nil {
 		return err
 	}
-	err = LoadGroups(ctx, s, []Group{g})
+	g = newComponentOptions(opts).group(g)
+	err = loadAndBuildGroups(ctx, s, []Group{g})
 	if err != nil {
 		return err
 	}
 
 	// Once the configuration struct is populated we can generate a new instance
 	// of the component and set the destination pointer.
-	nOuts := nm.Call([]reflect.Value{
-		reflect.ValueOf(ctx).Convert(nm.Type().In(0)),
-		smOut.Convert(nm.Type().In(1)),
-	})
-	nV, nErr := nOuts[0], nOuts[1]
-	nV = reflect.Indirect(nV)
-
-	if !nErr.IsNil() {
-		return nErr.Interface().(error)
+	nV, err := callNew(ctx, v, smOut)
+	if err != nil {
+		return err
 	}
+	nV = reflect.Indirect(nV)
 	if !nV.Type().ConvertibleTo(dv.Elem().Type()) {
 		return fmt.Errorf("cannot convert %s into %s", nV.Type(), dv.Elem().Type())
 	}
@@ -91,70 +91,214 @@ func NewComponent(ctx context.Context, s Source, v interface{}, destination inte
 	return nil
 }
 
+// callNew calls the New method of a verified component with the given
+// settings and any dependencies and returns the created value or the error
+// from New.
+func callNew(ctx context.Context, v interface{}, settings reflect.Value, dependencies ...reflect.Value) (reflect.Value, error) {
+	nm := reflect.ValueOf(v).MethodByName("New")
+	nOuts := nm.Call(append([]reflect.Value{
+		reflect.ValueOf(ctx).Convert(nm.Type().In(0)),
+		settings.Convert(nm.Type().In(1)),
+	}, dependencies...))
+	nV, nErr := nOuts[0], nOuts[1]
+	if !nErr.IsNil() {
+		return reflect.Value{}, nErr.Interface().(error)
+	}
+	return nV, nil
+}
+
+// ComponentError lists every way in which a value fails to satisfy the
+// Component contract.
+type ComponentError struct {
+	Type     string
+	Problems []string
+}
+
+func (e *ComponentError) Error() string {
+	return fmt.Sprintf("type %s is not a valid component: %s", e.Type, strings.Join(e.Problems, "; "))
+}
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
 // VerifyComponent checks if a given value implements the Component
-// contract.
+// contract. Every problem that is found is reported in a single
+// *ComponentError. This includes any error from converting the value
+// returned by Settings into a Group so that invalid configuration structs
+// are detected before any configuration is loaded.
 func VerifyComponent(v interface{}) error {
-	// Check for all required method names.
-	vv := reflect.ValueOf(v)
-	var hasSettings bool
-	var hasNew bool
-	for x := 0; x < vv.Type().NumMethod(); x = x + 1 {
-		fnName := vv.Type().Method(x).Name
-		switch fnName {
-		case "Settings":
-			hasSettings = true
-		case "New":
-			hasNew = true
-		default:
-		}
+	return verifyComponent(v, false)
+}
+
+// TestingT is the subset of testing.TB used by AssertComponent.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// AssertComponent reports every problem found by VerifyComponent as a test
+// error and returns true if there were none. It is intended for use in the
+// unit tests of packages that provide components:
+//
+//	func TestComponent(t *testing.T) {
+//	    settings.AssertComponent(t, &Component{})
+//	}
+func AssertComponent(t TestingT, v interface{}) bool {
+	t.Helper()
+	err := VerifyComponent(v)
+	if err == nil {
+		return true
 	}
-	if !hasSettings {
-		return fmt.Errorf("type %s does not have a `Settings() T` method", vv.Type())
+	var ce *ComponentError
+	if !errors.As(err, &ce) {
+		t.Errorf("%s", err.Error())
+		return false
 	}
-	if !hasNew {
-		return fmt.Errorf("type %s does not have a `New(ctx, T) (T2, error)` method", vv.Type())
+	for _, p := range ce.Problems {
+		t.Errorf("type %s is not a valid component: %s", ce.Type, p)
 	}
+	return false
+}
+
+// verifyComponent checks the Component contract. Components managed by a
+// Container may accept dependencies as additional arguments to New.
+func verifyComponent(v interface{}, dependencies bool) error {
+	if v == nil {
+		return &ComponentError{Type: "<nil>", Problems: []string{"component is nil"}}
+	}
+	vv := reflect.ValueOf(v)
+	e := &ComponentError{Type: vv.Type().String()}
+	problem := func(format string, args ...interface{}) {
+		e.Problems = append(e.Problems, fmt.Sprintf(format, args...))
+	}
+
 	sm := vv.MethodByName("Settings")
 	nm := vv.MethodByName("New")
+	if !sm.IsValid() {
+		problem("does not have a `Settings() T` method")
+	}
+	if !nm.IsValid() {
+		problem("does not have a `New(ctx, T) (T2, error)` method")
+	}
 
 	// Check that Settings implements the correct signature.
-	if sm.Type().NumIn() != 0 {
-		return fmt.Errorf("method Settings for %s must not take arguments", vv.Type())
+	validSettings := sm.IsValid()
+	if validSettings && sm.Type().NumIn() != 0 {
+		problem("method Settings must not take arguments")
+		validSettings = false
 	}
-	if sm.Type().NumOut() != 1 {
-		return fmt.Errorf("method Settings for %s must return only one value", vv.Type())
+	if validSettings && sm.Type().NumOut() != 1 {
+		problem("method Settings must return only one value")
+		validSettings = false
 	}
 
-	// Grab the return type of Settings for use in validating the New method.
-	smOut := sm.Type().Out(0)
-
 	// Check that the New method implements the correct signature.
-	if nm.Type().NumIn() != 2 {
-		return fmt.Errorf("method New for %s must take only two arguments", vv.Type())
-	}
-	if nm.Type().NumOut() != 2 {
-		return fmt.Errorf("method New for %s must return only two values", vv.Type())
-	}
-	if !reflect.TypeOf(context.Background()).ConvertibleTo(nm.Type().In(0)) {
-		return fmt.Errorf("method New for %s must accept context as the first argument", vv.Type())
+	if nm.IsValid() {
+		nt := nm.Type()
+		switch {
+		case nt.NumIn() < 2:
+			problem("method New must take at least two arguments")
+		case nt.NumIn() != 2 && !dependencies:
+			problem("method New must take only two arguments")
+		default:
+		}
+		if nt.NumIn() > 0 && !contextType.ConvertibleTo(nt.In(0)) {
+			problem("method New must accept context as the first argument")
+		}
+		if nt.NumIn() > 1 && validSettings && !sm.Type().Out(0).ConvertibleTo(nt.In(1)) {
+			problem("method New must accept an instance of Settings() return value")
+		}
+		if nt.NumOut() != 2 {
+			problem("method New must return only two values")
+		} else if out := nt.Out(1); !out.Implements(errorType) ||
+			(out.Kind() != reflect.Interface && out.Kind() != reflect.Ptr) {
+			problem("method New must return an error as the second value")
+		}
 	}
-	if !smOut.ConvertibleTo(nm.Type().In(1)) {
-		return fmt.Errorf("method New for %s must accept an instance of Settings() return value", vv.Type())
+
+	// Check that the settings can be converted.
+	if validSettings {
+		smOut := sm.Call(nil)[0]
+		switch {
+		case isNilValue(smOut.Interface()):
+			problem("method Settings returned nil")
+		case reflect.Indirect(smOut).Kind() != reflect.Struct:
+			problem("method Settings must return a struct or a pointer to a struct")
+		default:
+			if _, err := Convert(smOut.Interface()); err != nil {
+				problem("settings cannot be converted: %s", err.Error())
+			}
+		}
 	}
-	if nm.Type().Out(1).Name() != "error" {
-		return fmt.Errorf("method New for %s must return an error as the second value", vv.Type())
+
+	if len(e.Problems) > 0 {
+		return e
 	}
 	return nil
 }
 
 // GroupFromComponent works like Convert to change a struct into a Group but is able
-// to do so with an implementation of the Component contract.
-func GroupFromComponent(v interface{}) (Group, error) {
+// to do so with an implementation of the Component contract. The same options given
+// to NewComponent may be given here so that examples match the loaded configuration.
+func GroupFromComponent(v interface{}, opts ...ComponentOption) (Group, error) {
 	if err := VerifyComponent(v); err != nil {
 		return nil, err
 	}
 	vv := reflect.ValueOf(v)
 	sm := vv.MethodByName("Settings")
 	smOut := sm.Call(nil)[0]
-	return Convert(smOut.Interface())
+	g, err := Convert(smOut.Interface())
+	if err != nil {
+		return nil, err
+	}
+	return newComponentOptions(opts).group(g), nil
+}
+
+// ComponentOption modifies how NewComponent and GroupFromComponent arrange the
+// configuration of a component.
+type ComponentOption func(*componentOptions)
+
+type componentOptions struct {
+	path []string
+}
+
+func newComponentOptions(opts []ComponentOption) *componentOptions {
+	o := &componentOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithPath sets the path of keys that leads to the configuration of a component
+// in place of the name of its settings struct. This allows the same component to
+// be used more than once with different configuration. For example,
+// WithPath("primarydb") loads the settings from primarydb.* and
+// WithPath("db", "primary") loads them from db.primary.*.
+func WithPath(path ...string) ComponentOption {
+	return func(o *componentOptions) {
+		o.path = path
+	}
+}
+
+// group applies the options to the Group converted from a component.
+func (o *componentOptions) group(g Group) Group {
+	if len(o.path) < 1 {
+		return g
+	}
+	result := Group(&SettingGroup{
+		NameValue:        o.path[len(o.path)-1],
+		DescriptionValue: g.Description(),
+		GroupValues:      g.Groups(),
+		SettingValues:    g.Settings(),
+	})
+	for x := len(o.path) - 2; x >= 0; x = x - 1 {
+		result = &SettingGroup{
+			NameValue:   o.path[x],
+			GroupValues: []Group{result},
+		}
+	}
+	return result
 }
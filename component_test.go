@@ -3,13 +3,27 @@ package settings
 import (
 	"context"
 	"errors"
+	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 )
 
 type testConf struct {
 	Value string
 }
+
+// convertGroups converts a configuration struct for tests that need it as
+// a list of groups.
+func convertGroups(t *testing.T, v interface{}) []Group {
+	t.Helper()
+	g, err := Convert(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []Group{g}
+}
+
 type testItem struct {
 	Value string
 }
@@ -305,3 +319,151 @@ func TestNewComponent(t *testing.T) {
 		})
 	}
 }
+
+type namedError struct{}
+
+func (namedError) Error() string { return "error" }
+
+type newCustomErrorType struct{}
+
+func (*newCustomErrorType) New(context.Context, *testConf) (*testItem, *namedError) {
+	return &testItem{}, nil
+}
+func (*newCustomErrorType) Settings() *testConf { return &testConf{} }
+
+type newValueErrorType struct{}
+
+func (*newValueErrorType) New(context.Context, *testConf) (*testItem, namedError) {
+	return &testItem{}, namedError{}
+}
+func (*newValueErrorType) Settings() *testConf { return &testConf{} }
+
+type badSettingsConf struct {
+	V complex64
+}
+
+type badSettings struct{}
+
+func (*badSettings) New(context.Context, *badSettingsConf) (*testItem, error) {
+	return &testItem{}, nil
+}
+func (*badSettings) Settings() *badSettingsConf { return &badSettingsConf{} }
+
+type nilSettings struct{}
+
+func (*nilSettings) New(context.Context, *testConf) (*testItem, error) { return &testItem{}, nil }
+func (*nilSettings) Settings() *testConf                               { return nil }
+
+type manyProblems struct{}
+
+func (*manyProblems) New(int) (*testItem, int) { return &testItem{}, 0 }
+
+func TestVerifyComponent(t *testing.T) {
+	tests := []struct {
+		name     string
+		v        interface{}
+		problems []string
+	}{
+		{name: "valid", v: &testComponent{}},
+		{name: "custom error type", v: &newCustomErrorType{}},
+		{name: "nil", v: nil, problems: []string{"component is nil"}},
+		{name: "missing settings", v: &missingSettings{}, problems: []string{"`Settings() T`"}},
+		{name: "value error type", v: &newValueErrorType{}, problems: []string{"must return an error"}},
+		{name: "wrong error type", v: &newWrongErrorType{}, problems: []string{"must return an error"}},
+		{name: "bad settings", v: &badSettings{}, problems: []string{"settings cannot be converted"}},
+		{name: "nil settings", v: &nilSettings{}, problems: []string{"Settings returned nil"}},
+		{
+			name: "many problems",
+			v:    &manyProblems{},
+			problems: []string{
+				"`Settings() T`",
+				"at least two arguments",
+				"context as the first argument",
+				"must return an error",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyComponent(tt.v)
+			if len(tt.problems) == 0 {
+				if err != nil {
+					t.Errorf("VerifyComponent() error = %v", err)
+				}
+				return
+			}
+			var ce *ComponentError
+			if !errors.As(err, &ce) {
+				t.Fatalf("VerifyComponent() error = %v, want *ComponentError", err)
+			}
+			if len(ce.Problems) != len(tt.problems) {
+				t.Fatalf("VerifyComponent() problems = %v, want %d", ce.Problems, len(tt.problems))
+			}
+			for x, p := range tt.problems {
+				if !strings.Contains(ce.Problems[x], p) {
+					t.Errorf("problem %d = %s, want %s", x, ce.Problems[x], p)
+				}
+			}
+		})
+	}
+}
+
+type recordingT struct {
+	errors []string
+}
+
+func (*recordingT) Helper() {}
+func (r *recordingT) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertComponent(t *testing.T) {
+	AssertComponent(t, &testComponent{})
+	r := &recordingT{}
+	if AssertComponent(r, &manyProblems{}) {
+		t.Error("AssertComponent() = true for an invalid component")
+	}
+	if len(r.errors) != 4 {
+		t.Errorf("AssertComponent() reported %v, want 4 errors", r.errors)
+	}
+}
+
+func TestNewComponent_WithPath(t *testing.T) {
+	src := NewMapSource(map[string]interface{}{
+		"testconf":  map[string]interface{}{"value": "default"},
+		"primarydb": map[string]interface{}{"value": "primary"},
+		"db":        map[string]interface{}{"replica": map[string]interface{}{"value": "replica"}},
+	})
+	tests := []struct {
+		name string
+		opts []ComponentOption
+		want string
+	}{
+		{name: "no path", want: "default"},
+		{name: "single", opts: []ComponentOption{WithPath("primarydb")}, want: "primary"},
+		{name: "nested", opts: []ComponentOption{WithPath("db", "replica")}, want: "replica"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := new(testItem)
+			if err := NewComponent(context.Background(), src, &testComponent{}, r, tt.opts...); err != nil {
+				t.Fatal(err)
+			}
+			if r.Value != tt.want {
+				t.Errorf("Value = %s, want %s", r.Value, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroupFromComponent_WithPath(t *testing.T) {
+	g, err := GroupFromComponent(&testComponent{}, WithPath("db", "replica"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `DB_REPLICA_VALUE=""
+`
+	if got := ExampleEnvGroups([]Group{g}); !strings.HasSuffix(got, want) {
+		t.Errorf("ExampleEnvGroups() = %v, want suffix %v", got, want)
+	}
+}
@@ -0,0 +1,270 @@
+package settings
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// containerEntry tracks a single component managed by a Container.
+type containerEntry struct {
+	component interface{}
+	output    reflect.Type
+	// arguments are the types of any New arguments after the settings.
+	arguments []reflect.Type
+	// fields are the indexes of settings fields tagged with `inject:"true"`.
+	fields []int
+	value  reflect.Value
+	built  bool
+}
+
+// dependencies returns the types of everything the component needs from
+// other components in the order they are resolved.
+func (e *containerEntry) dependencies(settings reflect.Type) []reflect.Type {
+	deps := make([]reflect.Type, 0, len(e.arguments)+len(e.fields))
+	deps = append(deps, e.arguments...)
+	for _, x := range e.fields {
+		deps = append(deps, settings.Field(x).Type)
+	}
+	return deps
+}
+
+// Container builds a collection of components that depend on one another.
+// Each component implements the same contract as those given to
+// NewComponent except that the New method may accept additional arguments
+// after the settings. Any settings field tagged with `inject:"true"` is
+// also a dependency and must be exported. Dependencies are matched by type
+// to the output of exactly one other component in the container and
+// components are built in an order that satisfies every dependency.
+//
+// Components are started and closed through the Container in the same way
+// as with a Lifecycle, in the order they were built.
+type Container struct {
+	entries   []*containerEntry
+	lifecycle Lifecycle
+}
+
+// NewContainer creates a Container for the given components.
+func NewContainer(components ...interface{}) (*Container, error) {
+	c := &Container{}
+	for _, component := range components {
+		if err := c.Add(component); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// Add a component to the Container.
+func (c *Container) Add(component interface{}) error {
+	if err := verifyComponent(component, true); err != nil {
+		return err
+	}
+	nm := reflect.ValueOf(component).MethodByName("New")
+	e := &containerEntry{component: component, output: nm.Type().Out(0)}
+	for x := 2; x < nm.Type().NumIn(); x = x + 1 {
+		e.arguments = append(e.arguments, nm.Type().In(x))
+	}
+	st := settingsType(component)
+	if st.Kind() == reflect.Struct {
+		for x := 0; x < st.NumField(); x = x + 1 {
+			if st.Field(x).Tag.Get("inject") == "true" {
+				if !st.Field(x).IsExported() {
+					return fmt.Errorf("cannot inject unexported field %s of %s", st.Field(x).Name, st)
+				}
+				e.fields = append(e.fields, x)
+			}
+		}
+	}
+	c.entries = append(c.entries, e)
+	return nil
+}
+
+// settingsType returns the struct type produced by the Settings method of
+// a verified component.
+func settingsType(component interface{}) reflect.Type {
+	t := reflect.ValueOf(component).MethodByName("Settings").Type().Out(0)
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
+}
+
+// provider returns the single entry whose output satisfies the given type.
+func (c *Container) provider(t reflect.Type) (*containerEntry, error) {
+	var found []*containerEntry
+	for _, e := range c.entries {
+		if e.output.AssignableTo(t) {
+			found = append(found, e)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("no component provides %s", t)
+	case 1:
+		return found[0], nil
+	default:
+		outputs := make([]string, 0, len(found))
+		for _, e := range found {
+			outputs = append(outputs, e.output.String())
+		}
+		return nil, fmt.Errorf("multiple components provide %s: %s", t, strings.Join(outputs, ", "))
+	}
+}
+
+// order sorts the entries so that every component follows the components
+// it depends on. Components without a relationship keep the order in which
+// they were added.
+func (c *Container) order() ([]*containerEntry, error) {
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := make(map[*containerEntry]int, len(c.entries))
+	result := make([]*containerEntry, 0, len(c.entries))
+	var path []*containerEntry
+	var visit func(e *containerEntry) error
+	visit = func(e *containerEntry) error {
+		switch state[e] {
+		case visited:
+			return nil
+		case visiting:
+			var cycle []string
+			for x := len(path) - 1; x >= 0; x = x - 1 {
+				cycle = append([]string{path[x].output.String()}, cycle...)
+				if path[x] == e {
+					break
+				}
+			}
+			cycle = append(cycle, e.output.String())
+			return fmt.Errorf("dependency cycle: %s", strings.Join(cycle, " -> "))
+		default:
+		}
+		state[e] = visiting
+		path = append(path, e)
+		for _, dep := range e.dependencies(settingsType(e.component)) {
+			p, err := c.provider(dep)
+			if err != nil {
+				return fmt.Errorf("failed to resolve dependency of %s due to: %s", e.output, err.Error())
+			}
+			if err := visit(p); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[e] = visited
+		result = append(result, e)
+		return nil
+	}
+	for _, e := range c.entries {
+		if err := visit(e); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// Build loads the settings of every component from the given source and
+// creates each component once all of its dependencies are created. The
+// settings of each component are loaded exactly as NewComponent would load
+// them.
+func (c *Container) Build(ctx context.Context, s Source) error {
+	ordered, err := c.order()
+	if err != nil {
+		return err
+	}
+	for _, e := range ordered {
+		if err := c.build(ctx, s, e); err != nil {
+			return fmt.Errorf("failed to build %s due to: %s", e.output, err.Error())
+		}
+	}
+	return nil
+}
+
+func (c *Container) build(ctx context.Context, s Source, e *containerEntry) error {
+	if e.built {
+		return nil
+	}
+	config := reflect.ValueOf(e.component).MethodByName("Settings").Call(nil)[0]
+	for _, x := range e.fields {
+		f := reflect.Indirect(config).Field(x)
+		if !f.CanSet() {
+			t := reflect.Indirect(config).Type()
+			return fmt.Errorf("cannot inject field %s of %s because it cannot be set", t.Field(x).Name, t)
+		}
+		p, err := c.provider(f.Type())
+		if err != nil {
+			return err
+		}
+		f.Set(p.value)
+	}
+	g, err := Convert(config.Interface())
+	if err != nil {
+		return err
+	}
+	if err := loadAndBuildGroups(ctx, s, []Group{g}); err != nil {
+		return err
+	}
+	args := make([]reflect.Value, 0, len(e.arguments))
+	for _, t := range e.arguments {
+		p, err := c.provider(t)
+		if err != nil {
+			return err
+		}
+		args = append(args, p.value)
+	}
+	v, err := callNew(ctx, e.component, config, args...)
+	if err != nil {
+		return err
+	}
+	e.value = v
+	e.built = true
+	c.lifecycle.Add(v.Interface())
+	return nil
+}
+
+// Start calls Start on every built component that implements Starter in
+// the order the components were built.
+func (c *Container) Start(ctx context.Context) error {
+	return c.lifecycle.Start(ctx)
+}
+
+// Close calls Close on every built component that implements io.Closer in
+// the reverse of the order the components were built, except for one whose
+// Start failed. It may be called after Build fails to release the
+// components that were already built.
+func (c *Container) Close() error {
+	return c.lifecycle.Close()
+}
+
+// Resolve sets the destination to the value created by the component whose
+// output matches the destination type. The destination is a pointer created
+// with new(T) in the same way as for NewComponent. Resolve must be called
+// after Build.
+func (c *Container) Resolve(destination interface{}) error {
+	dv := reflect.ValueOf(destination)
+	if dv.Kind() != reflect.Ptr {
+		return fmt.Errorf("destination %s must be a pointer type", dv.Type())
+	}
+	if dv.IsNil() {
+		return fmt.Errorf("destination %s cannot be nil. use new(T) to make a pointer", dv.Type())
+	}
+	t := dv.Elem().Type()
+	for _, e := range c.entries {
+		if !e.built {
+			continue
+		}
+		if e.output.AssignableTo(t) {
+			dv.Elem().Set(e.value)
+			return nil
+		}
+		// Pointer outputs may also be copied into a destination of the
+		// element type, which matches the behavior of NewComponent.
+		if e.output.Kind() == reflect.Ptr && !e.value.IsNil() && e.output.Elem().ConvertibleTo(t) {
+			dv.Elem().Set(e.value.Elem().Convert(t))
+			return nil
+		}
+	}
+	return fmt.Errorf("no component built %s", t)
+}
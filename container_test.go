@@ -0,0 +1,176 @@
+package settings
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type containerLogConf struct {
+	Level string
+}
+
+type containerLogger struct {
+	level string
+}
+
+type containerLoggerComponent struct{}
+
+func (*containerLoggerComponent) Settings() *containerLogConf {
+	return &containerLogConf{Level: "info"}
+}
+func (*containerLoggerComponent) New(_ context.Context, c *containerLogConf) (*containerLogger, error) {
+	return &containerLogger{level: c.Level}, nil
+}
+
+type containerClientConf struct {
+	URL string
+}
+
+type containerClient struct {
+	url    string
+	logger *containerLogger
+}
+
+type containerClientComponent struct{}
+
+func (*containerClientComponent) Settings() *containerClientConf {
+	return &containerClientConf{}
+}
+func (*containerClientComponent) New(_ context.Context, c *containerClientConf, l *containerLogger) (*containerClient, error) {
+	return &containerClient{url: c.URL, logger: l}, nil
+}
+
+type containerServiceConf struct {
+	Client *containerClient `inject:"true"`
+	Name   string
+}
+
+type containerService struct {
+	name   string
+	client *containerClient
+}
+
+type containerServiceComponent struct{}
+
+func (*containerServiceComponent) Settings() *containerServiceConf {
+	return &containerServiceConf{}
+}
+func (*containerServiceComponent) New(_ context.Context, c *containerServiceConf) (*containerService, error) {
+	return &containerService{name: c.Name, client: c.Client}, nil
+}
+
+type containerCycleA struct{}
+type containerCycleB struct{}
+type containerCycleAComponent struct{}
+type containerCycleBComponent struct{}
+
+func (*containerCycleAComponent) Settings() *testConf { return &testConf{} }
+func (*containerCycleAComponent) New(_ context.Context, _ *testConf, _ *containerCycleB) (*containerCycleA, error) {
+	return &containerCycleA{}, nil
+}
+func (*containerCycleBComponent) Settings() *testConf { return &testConf{} }
+func (*containerCycleBComponent) New(_ context.Context, _ *testConf, _ *containerCycleA) (*containerCycleB, error) {
+	return &containerCycleB{}, nil
+}
+
+func TestContainer_Build(t *testing.T) {
+	// Components are added in reverse dependency order to force sorting.
+	c, err := NewContainer(
+		&containerServiceComponent{},
+		&containerClientComponent{},
+		&containerLoggerComponent{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewMapSource(map[string]interface{}{
+		"containerlogconf":     map[string]interface{}{"level": "debug"},
+		"containerclientconf":  map[string]interface{}{"url": "http://localhost"},
+		"containerserviceconf": map[string]interface{}{"name": "svc"},
+	})
+	if err := c.Build(context.Background(), s); err != nil {
+		t.Fatal(err)
+	}
+	svc := new(*containerService)
+	if err := c.Resolve(svc); err != nil {
+		t.Fatal(err)
+	}
+	logger := new(*containerLogger)
+	if err := c.Resolve(logger); err != nil {
+		t.Fatal(err)
+	}
+	if (*svc).name != "svc" || (*svc).client.url != "http://localhost" {
+		t.Errorf("service was not configured: %#v", *svc)
+	}
+	if (*svc).client.logger != *logger || (*logger).level != "debug" {
+		t.Error("logger was not shared with the client")
+	}
+	copied := new(containerLogger)
+	if err := c.Resolve(copied); err != nil || copied.level != "debug" {
+		t.Errorf("Resolve() = %v, %#v", err, copied)
+	}
+	if err := c.Resolve(new(testItem)); err == nil {
+		t.Error("resolved a type without a component")
+	}
+}
+
+type containerHiddenConf struct {
+	logger *containerLogger `inject:"true"`
+}
+
+type containerHiddenComponent struct{}
+
+func (*containerHiddenComponent) Settings() *containerHiddenConf { return &containerHiddenConf{} }
+func (*containerHiddenComponent) New(_ context.Context, c *containerHiddenConf) (*containerService, error) {
+	return &containerService{}, nil
+}
+
+func TestContainer_errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		components []interface{}
+		wantErr    string
+	}{
+		{
+			name:       "cycle",
+			components: []interface{}{&containerCycleAComponent{}, &containerCycleBComponent{}},
+			wantErr:    "dependency cycle: *settings.containerCycleA -> *settings.containerCycleB -> *settings.containerCycleA",
+		},
+		{
+			name:       "missing",
+			components: []interface{}{&containerClientComponent{}},
+			wantErr:    "no component provides *settings.containerLogger",
+		},
+		{
+			name: "ambiguous",
+			components: []interface{}{
+				&containerClientComponent{}, &containerLoggerComponent{}, &containerLoggerComponent{},
+			},
+			wantErr: "multiple components provide *settings.containerLogger",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewContainer(tt.components...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = c.Build(context.Background(), NewMapSource(map[string]interface{}{}))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Build() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewContainer_invalidComponent(t *testing.T) {
+	if _, err := NewContainer(&missingNew{}); err == nil {
+		t.Error("accepted an invalid component")
+	}
+	_, err := NewContainer(&containerLoggerComponent{}, &containerHiddenComponent{})
+	want := "cannot inject unexported field logger of settings.containerHiddenConf"
+	if err == nil || err.Error() != want {
+		t.Errorf("NewContainer() error = %v, want %s", err, want)
+	}
+}
@@ -1,10 +1,12 @@
 package settings
 
 import (
+	"encoding"
 	"errors"
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 )
 
 const (
@@ -12,6 +14,29 @@ const (
 	durationName = "time.Duration"
 )
 
+var (
+	secretType          = reflect.TypeOf(Secret(""))
+	timeType            = reflect.TypeOf(time.Time{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// isTextType returns true if the type can be loaded from a string through
+// the encoding.TextUnmarshaler interface.
+func isTextType(t reflect.Type) bool {
+	return reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// isSettingType returns true if a field of the type is managed by a single
+// setting rather than converted into a sub-tree.
+func isSettingType(t reflect.Type) bool {
+	return t.Kind() != reflect.Struct ||
+		t.String() == timeName ||
+		isTextType(t) ||
+		isOptionalType(t) ||
+		hasConverter(t)
+}
+
 type namer interface {
 	Name() string
 }
@@ -76,7 +101,22 @@ func Convert(v interface{}) (Group, error) {
 		currentF := current.Field
 		currentV := current.Value
 		currentVV := reflect.Indirect(currentV)
+		if currentF.Tag.Get("inject") == "true" {
+			// Injected fields are populated by a Container rather than
+			// loaded from a source.
+			continue
+		}
 		desc := currentF.Tag.Get("description")
+		if _, ok := lookupConverter(currentV.Type()); ok {
+			// Registered pointer types are given to the converter as-is
+			// rather than dereferenced so that nil values may be populated.
+			currentVV = currentV
+		}
+		if currentV.Kind() == reflect.Ptr && currentV.IsNil() && isSettingType(currentV.Type().Elem()) {
+			// Nil pointers to values are optional settings that are only
+			// allocated if a source has a value for them.
+			currentVV = currentV
+		}
 
 		if currentVV.Kind() == reflect.Struct && currentF.Anonymous {
 			for x := 0; x < currentVV.NumField(); x = x + 1 {
@@ -95,15 +135,47 @@ func Convert(v interface{}) (Group, error) {
 			// change the value.
 			return nil, fmt.Errorf("%s field %s.%s must be a pointer type", currentV.Type(), name, currentF.Name)
 		}
-		if currentVV.Kind() != reflect.Struct ||
-			currentVV.Type().String() == timeName {
-			set, err := settingFromValue(currentF.Name, desc, currentVV)
+		if currentVV.Kind() == reflect.Interface && hasComponents(currentVV.Type()) {
+			pg, err := newPluginGroup(currentF.Name, desc, currentVV)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"failed to convert %s.%s due to: %s",
+					g.NameValue, currentF.Name, err.Error(),
+				)
+			}
+			g.GroupValues = append(g.GroupValues, pg)
+			continue
+		}
+		if isSettingType(currentVV.Type()) {
+			base := &BaseSetting{
+				NameValue:        currentF.Name,
+				DescriptionValue: desc,
+				SensitiveValue:   currentF.Tag.Get("secret") == "true",
+				RequiredValue:    currentF.Tag.Get("required") == "true",
+			}
+			set, err := settingFromValue(base, currentVV)
 			if err != nil {
 				return nil, fmt.Errorf(
 					"failed to convert %s.%s due to: %s",
 					g.NameValue, currentF.Name, err.Error(),
 				)
 			}
+			if sep := currentF.Tag.Get("separator"); sep != "" {
+				ss, ok := elementOf(set).(interface{ setSeparator(string) })
+				if !ok || reflect.TypeOf(elementOf(set).Value()).Kind() != reflect.Slice {
+					return nil, fmt.Errorf(
+						"failed to convert %s.%s due to: separator is only supported for slices",
+						g.NameValue, currentF.Name,
+					)
+				}
+				ss.setSeparator(sep)
+			}
+			if err := setTimeFormat(set, currentF.Tag); err != nil {
+				return nil, fmt.Errorf(
+					"failed to convert %s.%s due to: %s",
+					g.NameValue, currentF.Name, err.Error(),
+				)
+			}
 			g.SettingValues = append(g.SettingValues, set)
 			continue
 		}
@@ -117,233 +189,122 @@ func Convert(v interface{}) (Group, error) {
 	return g, nil
 }
 
-func settingFromValue(name string, description string, v reflect.Value) (Setting, error) {
-	switch v.Type().String() {
-	case timeName:
-		s := &TimeSetting{
-			BaseSetting: &BaseSetting{
-				NameValue:        name,
-				DescriptionValue: description,
-			},
-		}
-		sv := reflect.Indirect(reflect.ValueOf(s))
-		sv.FieldByName("TimeValue").Set(v.Addr())
-		return s, nil
-	case durationName:
-		s := &DurationSetting{
-			BaseSetting: &BaseSetting{
-				NameValue:        name,
-				DescriptionValue: description,
-			},
+// setTimeFormat applies the layout and location tags of a field to the
+// setting created for it.
+func setTimeFormat(set Setting, tag reflect.StructTag) error {
+	layout, location := tag.Get("layout"), tag.Get("location")
+	if layout == "" && location == "" {
+		return nil
+	}
+	ts, ok := elementOf(set).(*TimeSetting)
+	if !ok {
+		return fmt.Errorf("layout and location are only supported for time.Time, *time.Time, and Optional[time.Time]")
+	}
+	if layout != "" {
+		ts.LayoutValues = parseLayouts(layout)
+	}
+	if location != "" {
+		loc, err := time.LoadLocation(location)
+		if err != nil {
+			return err
 		}
-		sv := reflect.Indirect(reflect.ValueOf(s))
-		sv.FieldByName("DurationValue").Set(v.Addr())
-		return s, nil
+		ts.LocationValue = loc
+	}
+	return nil
+}
+
+// kindTypes maps the kinds of basic types to the built-in type with the
+// same underlying representation.
+var kindTypes = map[reflect.Kind]reflect.Type{
+	reflect.Bool:    reflect.TypeOf(false),
+	reflect.Int:     reflect.TypeOf(int(0)),
+	reflect.Int8:    reflect.TypeOf(int8(0)),
+	reflect.Int16:   reflect.TypeOf(int16(0)),
+	reflect.Int32:   reflect.TypeOf(int32(0)),
+	reflect.Int64:   reflect.TypeOf(int64(0)),
+	reflect.Uint:    reflect.TypeOf(uint(0)),
+	reflect.Uint8:   reflect.TypeOf(uint8(0)),
+	reflect.Uint16:  reflect.TypeOf(uint16(0)),
+	reflect.Uint32:  reflect.TypeOf(uint32(0)),
+	reflect.Uint64:  reflect.TypeOf(uint64(0)),
+	reflect.Float32: reflect.TypeOf(float32(0)),
+	reflect.Float64: reflect.TypeOf(float64(0)),
+	reflect.String:  reflect.TypeOf(""),
+}
+
+// underlyingType returns the unnamed equivalent of a type, such as int
+// for `type Port int`, or nil if there is not one.
+func underlyingType(t reflect.Type) reflect.Type {
+	switch t.Kind() {
+	case reflect.Slice:
+		return reflect.SliceOf(t.Elem())
+	case reflect.Map:
+		return reflect.MapOf(t.Key(), t.Elem())
 	default:
+		return kindTypes[t.Kind()]
 	}
-	switch v.Kind() {
-	case reflect.Bool:
-		s := &BoolSetting{
-			BaseSetting: &BaseSetting{
-				NameValue:        name,
-				DescriptionValue: description,
-			},
-		}
-		sv := reflect.Indirect(reflect.ValueOf(s))
-		sv.FieldByName("BoolValue").Set(v.Addr())
-		return s, nil
-	case reflect.Int8:
-		s := &Int8Setting{
-			BaseSetting: &BaseSetting{
-				NameValue:        name,
-				DescriptionValue: description,
-			},
-		}
-		sv := reflect.Indirect(reflect.ValueOf(s))
-		sv.FieldByName("Int8Value").Set(v.Addr())
-		return s, nil
-	case reflect.Int16:
-		s := &Int16Setting{
-			BaseSetting: &BaseSetting{
-				NameValue:        name,
-				DescriptionValue: description,
-			},
+}
+
+func settingFromValue(base *BaseSetting, v reflect.Value) (Setting, error) {
+	if c, ok := lookupConverter(v.Type()); ok {
+		return &ConverterSetting{
+			BaseSetting:    base,
+			Converter:      c,
+			ConverterValue: v.Addr().Interface(),
+		}, nil
+	}
+	if v.Kind() == reflect.Ptr {
+		elem, err := elementSetting(base, v.Type().Elem())
+		if err != nil {
+			return nil, err
 		}
-		sv := reflect.Indirect(reflect.ValueOf(s))
-		sv.FieldByName("Int16Value").Set(v.Addr())
-		return s, nil
-	case reflect.Int32:
-		s := &Int32Setting{
-			BaseSetting: &BaseSetting{
-				NameValue:        name,
-				DescriptionValue: description,
-			},
+		return &PointerSetting{BaseSetting: base, PointerValue: v.Addr().Interface(), ElementValue: elem}, nil
+	}
+	if o, ok := v.Addr().Interface().(optional); ok {
+		elem, err := elementSetting(base, o.optionalValue().Type())
+		if err != nil {
+			return nil, err
 		}
-		sv := reflect.Indirect(reflect.ValueOf(s))
-		sv.FieldByName("Int32Value").Set(v.Addr())
-		return s, nil
-	case reflect.Int64:
-		s := &Int64Setting{
-			BaseSetting: &BaseSetting{
-				NameValue:        name,
-				DescriptionValue: description,
-			},
+		return &OptionalSetting{BaseSetting: base, OptionalValue: o, ElementValue: elem}, nil
+	}
+	if v.Type() == secretType {
+		base.SensitiveValue = true
+	}
+	if b, ok := builtinTypes[v.Type()]; ok {
+		return b.bind(base, v), nil
+	}
+	if isTextType(v.Type()) {
+		return &TextSetting{
+			BaseSetting: base,
+			TextValue:   v.Addr().Interface().(encoding.TextUnmarshaler),
+		}, nil
+	}
+	// Named types, such as `type Port int`, are managed through a pointer
+	// to the built-in type that shares their underlying representation.
+	if u := underlyingType(v.Type()); u != nil {
+		if b, ok := builtinTypes[u]; ok {
+			return b.bind(base, v.Addr().Convert(reflect.PointerTo(u)).Elem()), nil
 		}
-		sv := reflect.Indirect(reflect.ValueOf(s))
-		sv.FieldByName("Int64Value").Set(v.Addr())
-		return s, nil
-	case reflect.Map:
-		vTypeStored := v.Type()
-		switch vTypeStored.String() {
-		case "map[string][]string":
-			s := &StringMapStringSliceSetting{
-				BaseSetting: &BaseSetting{
-					NameValue:        name,
-					DescriptionValue: description,
-				},
-			}
-			sv := reflect.Indirect(reflect.ValueOf(s))
-			sv.FieldByName("StringMapStringSliceValue").Set(v.Addr())
-			return s, nil
-		case "map[string]string":
-			s := &StringMapStringSetting{
-				BaseSetting: &BaseSetting{
-					NameValue:        name,
-					DescriptionValue: description,
-				},
+	}
+	if v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String {
+		// Check that the values can be converted now rather than waiting
+		// for a value to be loaded.
+		elem := v.Type().Elem()
+		if elem.Kind() != reflect.Interface || elem.NumMethod() != 0 {
+			if _, err := settingFromValue(&BaseSetting{}, reflect.New(elem).Elem()); err != nil {
+				return nil, fmt.Errorf("unknown map value type for setting %s: %s", v.Type(), err.Error())
 			}
-			sv := reflect.Indirect(reflect.ValueOf(s))
-			sv.FieldByName("StringMapStringValue").Set(v.Addr())
-			return s, nil
-		default:
-			return nil, fmt.Errorf("unknown map value type for setting %s", vTypeStored)
-		}
-	case reflect.Uint:
-		s := &UintSetting{
-			BaseSetting: &BaseSetting{
-				NameValue:        name,
-				DescriptionValue: description,
-			},
-		}
-		sv := reflect.Indirect(reflect.ValueOf(s))
-		sv.FieldByName("UintValue").Set(v.Addr())
-		return s, nil
-	case reflect.Uint8:
-		s := &Uint8Setting{
-			BaseSetting: &BaseSetting{
-				NameValue:        name,
-				DescriptionValue: description,
-			},
-		}
-		sv := reflect.Indirect(reflect.ValueOf(s))
-		sv.FieldByName("Uint8Value").Set(v.Addr())
-		return s, nil
-	case reflect.Uint16:
-		s := &Uint16Setting{
-			BaseSetting: &BaseSetting{
-				NameValue:        name,
-				DescriptionValue: description,
-			},
-		}
-		sv := reflect.Indirect(reflect.ValueOf(s))
-		sv.FieldByName("Uint16Value").Set(v.Addr())
-		return s, nil
-	case reflect.Uint32:
-		s := &Uint32Setting{
-			BaseSetting: &BaseSetting{
-				NameValue:        name,
-				DescriptionValue: description,
-			},
-		}
-		sv := reflect.Indirect(reflect.ValueOf(s))
-		sv.FieldByName("Uint32Value").Set(v.Addr())
-		return s, nil
-	case reflect.Uint64:
-		s := &Uint64Setting{
-			BaseSetting: &BaseSetting{
-				NameValue:        name,
-				DescriptionValue: description,
-			},
-		}
-		sv := reflect.Indirect(reflect.ValueOf(s))
-		sv.FieldByName("Uint64Value").Set(v.Addr())
-		return s, nil
-	case reflect.Int:
-		s := &IntSetting{
-			BaseSetting: &BaseSetting{
-				NameValue:        name,
-				DescriptionValue: description,
-			},
-		}
-		sv := reflect.Indirect(reflect.ValueOf(s))
-		sv.FieldByName("IntValue").Set(v.Addr())
-		return s, nil
-	case reflect.Float32:
-		s := &Float32Setting{
-			BaseSetting: &BaseSetting{
-				NameValue:        name,
-				DescriptionValue: description,
-			},
-		}
-		sv := reflect.Indirect(reflect.ValueOf(s))
-		sv.FieldByName("Float32Value").Set(v.Addr())
-		return s, nil
-	case reflect.Float64:
-		s := &Float64Setting{
-			BaseSetting: &BaseSetting{
-				NameValue:        name,
-				DescriptionValue: description,
-			},
 		}
-		sv := reflect.Indirect(reflect.ValueOf(s))
-		sv.FieldByName("Float64Value").Set(v.Addr())
-		return s, nil
-	case reflect.String:
-		s := &StringSetting{
-			BaseSetting: &BaseSetting{
-				NameValue:        name,
-				DescriptionValue: description,
-			},
-		}
-		sv := reflect.Indirect(reflect.ValueOf(s))
-		sv.FieldByName("StringValue").Set(v.Addr())
-		return s, nil
+		return &MapSetting{
+			BaseSetting: base,
+			MapValue:    v.Addr().Interface(),
+		}, nil
+	}
+	switch v.Kind() {
+	case reflect.Map:
+		return nil, fmt.Errorf("unknown map value type for setting %s", v.Type())
 	case reflect.Slice:
-		if v.Type().Elem().String() == durationName {
-			s := &DurationSliceSetting{
-				BaseSetting: &BaseSetting{
-					NameValue:        name,
-					DescriptionValue: description,
-				},
-			}
-			sv := reflect.Indirect(reflect.ValueOf(s))
-			sv.FieldByName("DurationSliceValue").Set(v.Addr())
-			return s, nil
-		}
-		switch v.Type().Elem().Kind() {
-		case reflect.String:
-			s := &StringSliceSetting{
-				BaseSetting: &BaseSetting{
-					NameValue:        name,
-					DescriptionValue: description,
-				},
-			}
-			sv := reflect.Indirect(reflect.ValueOf(s))
-			sv.FieldByName("StringSliceValue").Set(v.Addr())
-			return s, nil
-		case reflect.Int:
-			s := &IntSliceSetting{
-				BaseSetting: &BaseSetting{
-					NameValue:        name,
-					DescriptionValue: description,
-				},
-			}
-			sv := reflect.Indirect(reflect.ValueOf(s))
-			sv.FieldByName("IntSliceValue").Set(v.Addr())
-			return s, nil
-		default:
-			return nil, fmt.Errorf("unknown setting type []%s", v.Type().Elem().Kind())
-		}
+		return nil, fmt.Errorf("unknown setting type %s", v.Type())
 	default:
 		return nil, fmt.Errorf("unknown setting type %s", v.Kind())
 	}
@@ -1,7 +1,11 @@
 package settings
 
 import (
+	"errors"
+	"fmt"
+	"net"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -20,6 +24,35 @@ func (*described) Description() string {
 	return "TEST"
 }
 
+type testLevel int
+
+type testPort int
+
+type testNames []string
+
+func (l testLevel) MarshalText() ([]byte, error) {
+	switch l {
+	case 0:
+		return []byte("info"), nil
+	case 1:
+		return []byte("debug"), nil
+	default:
+		return nil, errors.New("unknown level")
+	}
+}
+
+func (l *testLevel) UnmarshalText(b []byte) error {
+	switch string(b) {
+	case "info":
+		*l = 0
+	case "debug":
+		*l = 1
+	default:
+		return fmt.Errorf("unknown level %s", b)
+	}
+	return nil
+}
+
 type inner struct {
 	V string
 }
@@ -252,6 +285,80 @@ func TestConvert(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "struct/[]bool",
+			v:    &(struct{ V []bool }{V: []bool{true}}),
+			want: &SettingGroup{
+				SettingValues: []Setting{
+					NewBoolSliceSetting("V", "", []bool{true}),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "struct/[]float64",
+			v:    &(struct{ V []float64 }{V: []float64{1.5}}),
+			want: &SettingGroup{
+				SettingValues: []Setting{
+					NewFloat64SliceSetting("V", "", []float64{1.5}),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "struct/[]uint",
+			v:    &(struct{ V []uint }{V: []uint{1}}),
+			want: &SettingGroup{
+				SettingValues: []Setting{
+					NewUintSliceSetting("V", "", []uint{1}),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "struct/[]uint8",
+			v:    &(struct{ V []uint8 }{V: []uint8{1}}),
+			want: &SettingGroup{
+				SettingValues: []Setting{
+					NewUint8SliceSetting("V", "", []uint8{1}),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "struct/[]time.Time",
+			v:    &(struct{ V []time.Time }{V: []time.Time{time.Date(1999, time.January, 1, 0, 0, 0, 0, time.UTC)}}),
+			want: &SettingGroup{
+				SettingValues: []Setting{
+					NewTimeSliceSetting("V", "", []time.Time{time.Date(1999, time.January, 1, 0, 0, 0, 0, time.UTC)}),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "struct/separator",
+			v: &(struct {
+				V []string `separator:","`
+			}{V: []string{"a"}}),
+			want: &SettingGroup{
+				SettingValues: []Setting{
+					func() Setting {
+						s := NewStringSliceSetting("V", "", []string{"a"})
+						s.SeparatorValue = ","
+						return s
+					}(),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "struct/separator on scalar",
+			v: &(struct {
+				V string `separator:","`
+			}{}),
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name: "struct/map[string][]string",
 			v: &(struct{ V map[string][]string }{
@@ -276,6 +383,194 @@ func TestConvert(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "struct/map[string]int",
+			v:    &(struct{ V map[string]int }{V: map[string]int{"a": 1}}),
+			want: &SettingGroup{
+				SettingValues: []Setting{
+					NewMapSetting("V", "", &map[string]int{"a": 1}),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "struct/map[string]interface{}",
+			v:    &(struct{ V map[string]interface{} }{}),
+			want: &SettingGroup{
+				SettingValues: []Setting{
+					NewMapSetting("V", "", new(map[string]interface{})),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "struct/map unsupported value",
+			v:       &(struct{ V map[string]complex64 }{}),
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "struct/map unsupported key",
+			v:       &(struct{ V map[int]string }{}),
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "struct/ByteSize",
+			v:    &(struct{ V ByteSize }{V: Mebibyte}),
+			want: &SettingGroup{
+				SettingValues: []Setting{
+					NewByteSizeSetting("V", "", Mebibyte),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "struct/layout",
+			v: &(struct {
+				V time.Time `layout:"DateOnly|01/02/2006"`
+			}{}),
+			want: &SettingGroup{
+				SettingValues: []Setting{
+					NewLayoutTimeSetting("V", "", time.Time{}, time.DateOnly, "01/02/2006"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "struct/location",
+			v: &(struct {
+				V time.Time `location:"UTC"`
+			}{}),
+			want: &SettingGroup{
+				SettingValues: []Setting{
+					func() Setting {
+						s := NewTimeSetting("V", "", time.Time{})
+						s.LocationValue = time.UTC
+						return s
+					}(),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "struct/unknown location",
+			v: &(struct {
+				V time.Time `location:"Nowhere/Special"`
+			}{}),
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "struct/layout on pointer",
+			v: &(struct {
+				V *time.Time `layout:"DateOnly"`
+			}{}),
+			want: &SettingGroup{
+				SettingValues: []Setting{
+					func() Setting {
+						s := NewPointerSetting("V", "", new(*time.Time))
+						s.ElementValue.(*TimeSetting).LayoutValues = []string{time.DateOnly}
+						return s
+					}(),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "struct/layout on slice",
+			v: &(struct {
+				V []time.Time `layout:"DateOnly"`
+			}{}),
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "struct/layout on non-time",
+			v: &(struct {
+				V string `layout:"DateOnly"`
+			}{}),
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "struct/nil pointer",
+			v:    &(struct{ V *int }{}),
+			want: &SettingGroup{
+				SettingValues: []Setting{
+					NewPointerSetting("V", "", new(*int)),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "struct/nil pointer unsupported",
+			v:       &(struct{ V *complex64 }{}),
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "struct/optional",
+			v:    &(struct{ V Optional[string] }{}),
+			want: &SettingGroup{
+				SettingValues: []Setting{
+					NewOptionalSetting("V", "", &Optional[string]{}),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "struct/optional unsupported",
+			v:       &(struct{ V Optional[complex64] }{}),
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "struct/named int",
+			v:    &(struct{ V testPort }{V: 80}),
+			want: &SettingGroup{
+				SettingValues: []Setting{
+					NewIntSetting("V", "", 80),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "struct/named slice",
+			v:    &(struct{ V testNames }{V: testNames{"a"}}),
+			want: &SettingGroup{
+				SettingValues: []Setting{
+					NewStringSliceSetting("V", "", []string{"a"}),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "struct/unsupported",
+			v:       &(struct{ V complex64 }{}),
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "struct/net.IP",
+			v:    &(struct{ V net.IP }{V: net.IPv4(127, 0, 0, 1)}),
+			want: &SettingGroup{
+				SettingValues: []Setting{
+					NewTextSetting("V", "", func() *net.IP { ip := net.IPv4(127, 0, 0, 1); return &ip }()),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "struct/text unmarshaler",
+			v:    &(struct{ V testLevel }{V: 1}),
+			want: &SettingGroup{
+				SettingValues: []Setting{
+					NewTextSetting("V", "", func() *testLevel { l := testLevel(1); return &l }()),
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "struct/named",
 			v:    &named{},
@@ -306,6 +601,22 @@ func TestConvert(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "struct/secret",
+			v: &(struct {
+				V string `secret:"true"`
+			}{V: "a"}),
+			want: &SettingGroup{
+				SettingValues: []Setting{
+					func() Setting {
+						s := NewStringSetting("V", "", "a")
+						s.SensitiveValue = true
+						return s
+					}(),
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "struct/embedded",
 			v:    &embedded{&inner{V: "a"}},
@@ -351,3 +662,13 @@ func TestConvert(t *testing.T) {
 		})
 	}
 }
+
+func TestConvert_unsupportedSlice(t *testing.T) {
+	_, err := Convert(&(struct{ V []ByteSize }{}))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !strings.Contains(err.Error(), "unknown setting type []settings.ByteSize") {
+		t.Errorf("Convert() error = %s, want it to name []settings.ByteSize", err.Error())
+	}
+}
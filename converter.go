@@ -0,0 +1,54 @@
+package settings
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Converter adds support for a type that the library cannot otherwise
+// load. Converters are registered for a specific type with
+// RegisterConverter and are consulted by Convert before any of the
+// built-in conversions.
+type Converter interface {
+	// Parse converts a raw value from a Source into the registered type.
+	Parse(v interface{}) (interface{}, error)
+	// Render returns the text displayed for a value in examples.
+	Render(v interface{}) string
+	// TypeHint returns the name of the type displayed in examples.
+	TypeHint() string
+}
+
+var converters = struct {
+	sync.RWMutex
+	m map[reflect.Type]Converter
+}{m: make(map[reflect.Type]Converter)}
+
+// RegisterConverter installs a Converter for the given type. Registering a
+// type more than once replaces the previous Converter. Pointer types, such
+// as *regexp.Regexp, are matched exactly and are not dereferenced when
+// converting a struct so that the Converter may allocate new values.
+func RegisterConverter(t reflect.Type, c Converter) {
+	converters.Lock()
+	defer converters.Unlock()
+	converters.m[t] = c
+}
+
+// unregisterConverter removes the Converter for the given type so that
+// tests can undo a registration.
+func unregisterConverter(t reflect.Type) {
+	converters.Lock()
+	defer converters.Unlock()
+	delete(converters.m, t)
+}
+
+func lookupConverter(t reflect.Type) (Converter, bool) {
+	converters.RLock()
+	defer converters.RUnlock()
+	c, ok := converters.m[t]
+	return c, ok
+}
+
+func hasConverter(t reflect.Type) bool {
+	_, ok := lookupConverter(t)
+	return ok
+}
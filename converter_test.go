@@ -0,0 +1,113 @@
+package settings
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cast"
+)
+
+type regexpConverter struct{}
+
+func (regexpConverter) Parse(v interface{}) (interface{}, error) {
+	s, err := cast.ToStringE(v)
+	if err != nil {
+		return nil, err
+	}
+	return regexp.Compile(s)
+}
+
+func (regexpConverter) Render(v interface{}) string {
+	if r := v.(*regexp.Regexp); r != nil {
+		return r.String()
+	}
+	return ""
+}
+
+func (regexpConverter) TypeHint() string {
+	return "regexp"
+}
+
+// registerTestConverter installs a Converter for the duration of a test.
+func registerTestConverter(t *testing.T, typ reflect.Type, c Converter) {
+	t.Helper()
+	RegisterConverter(typ, c)
+	t.Cleanup(func() { unregisterConverter(typ) })
+}
+
+type converterConf struct {
+	Pattern  *regexp.Regexp `description:"a pattern"`
+	Fallback *regexp.Regexp
+}
+
+func TestConverter(t *testing.T) {
+	registerTestConverter(t, reflect.TypeOf(&regexp.Regexp{}), regexpConverter{})
+	conf := &converterConf{Fallback: regexp.MustCompile("^a+$")}
+	g, err := Convert(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &SettingGroup{
+		NameValue: "converterConf",
+		SettingValues: []Setting{
+			NewConverterSetting("Fallback", "", &conf.Fallback, regexpConverter{}),
+			NewConverterSetting("Pattern", "a pattern", &conf.Pattern, regexpConverter{}),
+		},
+	}
+	if !reflect.DeepEqual(g, want) {
+		t.Errorf("Convert() = %v, want %v", g, want)
+	}
+
+	example := ExampleYamlGroups([]Group{g})
+	if !strings.Contains(example, "# (regexp) a pattern\n  pattern: \"\"\n") ||
+		!strings.Contains(example, "fallback: \"^a+$\"") {
+		t.Errorf("ExampleYamlGroups() = %s", example)
+	}
+
+	s := NewMapSource(map[string]interface{}{
+		"converterconf": map[string]interface{}{"pattern": "^b+$"},
+	})
+	if err = LoadGroups(context.Background(), s, []Group{g}); err != nil {
+		t.Fatal(err)
+	}
+	if conf.Pattern == nil || !conf.Pattern.MatchString("bbb") {
+		t.Errorf("LoadGroups() Pattern = %v", conf.Pattern)
+	}
+
+	s = NewMapSource(map[string]interface{}{
+		"converterconf": map[string]interface{}{"pattern": "("},
+	})
+	if err = LoadGroups(context.Background(), s, []Group{g}); err == nil {
+		t.Error("LoadGroups() accepted bad input as good")
+	}
+}
+
+func TestUnregisterConverter(t *testing.T) {
+	typ := reflect.TypeOf(&regexp.Regexp{})
+	t.Run("registered", func(t *testing.T) {
+		registerTestConverter(t, typ, regexpConverter{})
+		if !hasConverter(typ) {
+			t.Error("hasConverter() = false during the test")
+		}
+	})
+	if hasConverter(typ) {
+		t.Error("hasConverter() = true after the test finished")
+	}
+}
+
+type wrongConverter struct{ regexpConverter }
+
+func (wrongConverter) Parse(interface{}) (interface{}, error) {
+	return 1, nil
+}
+
+func TestConverterSetting_wrongType(t *testing.T) {
+	var r *regexp.Regexp
+	s := NewConverterSetting("r", "", &r, wrongConverter{})
+	if err := s.SetValue("a"); err == nil {
+		t.Error("SetValue() accepted a value of the wrong type")
+	}
+}
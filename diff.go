@@ -0,0 +1,128 @@
+package settings
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// DiffKind describes how a setting differs between two sources.
+type DiffKind string
+
+const (
+	// SettingAdded means the setting only exists when loading the new
+	// source, such as an option of a newly selected PluginGroup type, and
+	// the new source has a value for it.
+	SettingAdded DiffKind = "added"
+	// SettingRemoved means the setting only exists when loading the old
+	// source and the old source has a value for it.
+	SettingRemoved DiffKind = "removed"
+	// SettingChanged means the effective value of the setting differs
+	// between the sources.
+	SettingChanged DiffKind = "changed"
+)
+
+// SettingDiff describes a single setting that differs between two sources.
+// Old and New hold the effective values, including defaults, in the same
+// form as DumpJSONGroups. They are nil when the setting does not exist in
+// one of the trees, such as when a PluginGroup selects another option. The
+// values of sensitive settings are redacted.
+type SettingDiff struct {
+	// Path is the full path of the setting including all group names.
+	Path []string
+	Kind DiffKind
+	Old  interface{}
+	New  interface{}
+}
+
+// String renders the difference as a single line of text.
+func (d SettingDiff) String() string {
+	path := strings.Join(d.Path, ".")
+	switch d.Kind {
+	case SettingAdded:
+		return fmt.Sprintf("+ %s = %s", path, jsonText(d.New))
+	case SettingRemoved:
+		return fmt.Sprintf("- %s = %s", path, jsonText(d.Old))
+	default:
+		return fmt.Sprintf("~ %s = %s -> %s", path, jsonText(d.Old), jsonText(d.New))
+	}
+}
+
+// effectiveValue is the result of loading a single setting.
+type effectiveValue struct {
+	path    []string
+	found   bool
+	value   interface{}
+	display interface{}
+}
+
+// effectiveValues loads every setting from the source into copies of the
+// groups, exactly like ValidateSource, and returns the resulting values.
+// A missing required value is not an error because the default is still
+// the effective value of the setting.
+func effectiveValues(ctx context.Context, s Source, groups []Group) ([]effectiveValue, error) {
+	var result []effectiveValue
+	_, err := loadWalk(ctx, s, detachGroups(groups), func(g Group, l settingLoad) error {
+		if l.err != nil && l.err != errValueRequired {
+			return stopOnError(g, l)
+		}
+		ev := effectiveValue{path: l.path, found: l.found, value: dumpValue(l.setting.Value())}
+		ev.display = ev.value
+		if text, ok := formatTime(l.setting, l.setting.Value()); ok {
+			ev.value, ev.display = text, text
+		}
+		if redacted, ok := redactedValue(l.setting); ok {
+			ev.display = redacted
+		}
+		result = append(result, ev)
+		return nil
+	})
+	return result, err
+}
+
+// DiffSources loads the given groups from an old and a new source and
+// reports every setting whose effective value differs between them. A
+// setting that one source stops setting is only reported if its default
+// differs from the value that was set. Settings that only exist in one of
+// the trees, such as the options of a PluginGroup, are added or removed if
+// that source has a value for them. Values are loaded into copies of the
+// groups so the groups are never changed, no component is created, and no
+// Builder is called. Settings are reported in the order they are loaded
+// from the old source followed by any that only exist when loading from
+// the new source.
+func DiffSources(ctx context.Context, from Source, to Source, groups []Group) ([]SettingDiff, error) {
+	before, err := effectiveValues(ctx, from, groups)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load old configuration due to: %s", err.Error())
+	}
+	after, err := effectiveValues(ctx, to, groups)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load new configuration due to: %s", err.Error())
+	}
+	afterByPath := make(map[string]effectiveValue, len(after))
+	for _, ev := range after {
+		afterByPath[strings.Join(ev.path, ".")] = ev
+	}
+	var diffs []SettingDiff
+	seen := make(map[string]bool, len(before))
+	for _, b := range before {
+		key := strings.Join(b.path, ".")
+		seen[key] = true
+		a, exists := afterByPath[key]
+		switch {
+		case !exists && b.found:
+			diffs = append(diffs, SettingDiff{Path: b.path, Kind: SettingRemoved, Old: b.display})
+		case !exists:
+		case !reflect.DeepEqual(b.value, a.value):
+			diffs = append(diffs, SettingDiff{Path: b.path, Kind: SettingChanged, Old: b.display, New: a.display})
+		default:
+		}
+	}
+	for _, a := range after {
+		if !seen[strings.Join(a.path, ".")] && a.found {
+			diffs = append(diffs, SettingDiff{Path: a.path, Kind: SettingAdded, New: a.display})
+		}
+	}
+	return diffs, nil
+}
@@ -0,0 +1,108 @@
+package settings
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andreyvit/diff"
+)
+
+type diffConf struct {
+	Host     string
+	Port     int
+	Timeout  time.Duration
+	Tags     []string
+	Password Secret
+	Cache    testCache
+}
+
+func newDiffConf() *diffConf {
+	return &diffConf{Host: "localhost", Port: 80, Timeout: time.Second, Password: "default"}
+}
+
+func TestDiffSources(t *testing.T) {
+	registerTestCaches(t)
+	conf := newDiffConf()
+	g, err := Convert(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := NewMapSource(map[string]interface{}{
+		"diffconf": map[string]interface{}{
+			"host":     "old.example.com",
+			"port":     8080,
+			"timeout":  "1s",
+			"password": "hunter2",
+			"cache":    map[string]interface{}{"type": "memory", "memory": map[string]interface{}{"size": 10}},
+		},
+	})
+	to := NewMapSource(map[string]interface{}{
+		"diffconf": map[string]interface{}{
+			"host":     "new.example.com",
+			"port":     "8080",
+			"tags":     "a b",
+			"password": "hunter3",
+			"cache":    map[string]interface{}{"type": "redis", "redis": map[string]interface{}{"host": "redis"}},
+		},
+	})
+	diffs, err := DiffSources(context.Background(), from, to, []Group{g})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := make([]string, 0, len(diffs))
+	for _, d := range diffs {
+		lines = append(lines, d.String())
+	}
+	got := strings.Join(lines, "\n")
+	want := strings.Join([]string{
+		`~ diffConf.Password = "<redacted>" -> "<redacted>"`,
+		`~ diffConf.Tags = [] -> ["a","b"]`,
+		`~ diffConf.Host = "old.example.com" -> "new.example.com"`,
+		`~ diffConf.Cache.type = "memory" -> "redis"`,
+		`- diffConf.Cache.memory.Size = 10`,
+		`+ diffConf.Cache.redis.Host = "redis"`,
+	}, "\n")
+	if got != want {
+		t.Errorf("DiffSources() = %v, want %v\n%s", got, want, diff.LineDiff(got, want))
+	}
+	// The timeout is no longer set but its default is the same value.
+	if diffs[4].Kind != SettingRemoved || diffs[4].Old != 10 || diffs[4].New != nil {
+		t.Errorf("DiffSources() removed = %#v", diffs[4])
+	}
+	if !reflect.DeepEqual(conf, newDiffConf()) {
+		t.Errorf("DiffSources() changed the settings: %#v", conf)
+	}
+}
+
+func TestDiffSources_error(t *testing.T) {
+	registerTestCaches(t)
+	g, err := Convert(newDiffConf())
+	if err != nil {
+		t.Fatal(err)
+	}
+	bad := NewMapSource(map[string]interface{}{"diffconf": map[string]interface{}{"port": "http"}})
+	_, err = DiffSources(context.Background(), NewMapSource(map[string]interface{}{}), bad, []Group{g})
+	if err == nil || !strings.HasPrefix(err.Error(), "failed to load new configuration due to: ") {
+		t.Errorf("DiffSources() error = %v", err)
+	}
+}
+
+func TestDiffSources_default(t *testing.T) {
+	registerTestCaches(t)
+	g, err := Convert(newDiffConf())
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := NewMapSource(map[string]interface{}{"diffconf": map[string]interface{}{"port": 8080, "host": "localhost"}})
+	diffs, err := DiffSources(context.Background(), from, NewMapSource(map[string]interface{}{}), []Group{g})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []SettingDiff{{Path: []string{"diffConf", "Port"}, Kind: SettingChanged, Old: 8080, New: 80}}
+	if !reflect.DeepEqual(diffs, want) {
+		t.Errorf("DiffSources() = %#v, want %#v", diffs, want)
+	}
+}
@@ -0,0 +1,150 @@
+package settings
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// redactedDisplay replaces the value of any sensitive setting that has been
+// given a value. Sensitive settings without a value are rendered as empty so
+// that a dump still shows which secrets are missing.
+const redactedDisplay = "<redacted>"
+
+func isSensitive(s Setting) bool {
+	sn, ok := s.(Sensitive)
+	return ok && sn.Sensitive()
+}
+
+// redactedValue returns the value of the setting or, if the setting is
+// sensitive, a placeholder that only indicates whether it was set.
+func redactedValue(s Setting) (interface{}, bool) {
+	if !isSensitive(s) {
+		return s.Value(), false
+	}
+	v := s.Value()
+	if v == nil || reflect.ValueOf(v).IsZero() {
+		return "", true
+	}
+	return redactedDisplay, true
+}
+
+// dumpValue adapts a setting value into a type that renders in YAML and
+// JSON the same way that the sources of those formats parse it.
+func dumpValue(v interface{}) interface{} {
+	switch vv := v.(type) {
+	case time.Duration:
+		return vv.String()
+	case time.Time:
+		return vv.Format(time.RFC3339Nano)
+	default:
+	}
+	if c, ok := lookupConverter(reflect.TypeOf(v)); ok {
+		return c.Render(v)
+	}
+	if text, ok := marshalText(v); ok {
+		return text
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		return dumpValue(rv.Elem().Interface())
+	}
+	if rv.Kind() == reflect.Slice {
+		result := make([]interface{}, 0, rv.Len())
+		for x := 0; x < rv.Len(); x = x + 1 {
+			result = append(result, dumpValue(rv.Index(x).Interface()))
+		}
+		return result
+	}
+	if rv.Kind() == reflect.Map {
+		result := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			result[fmt.Sprint(iter.Key().Interface())] = dumpValue(iter.Value().Interface())
+		}
+		return result
+	}
+	return v
+}
+
+// dumpTree gathers the current values of all settings in the given groups
+// into nested maps keyed by the lower case names used for lookups.
+func dumpTree(groups []Group) map[string]interface{} {
+	root := make(map[string]interface{})
+	_ = walkGroups(groups, func(path []string, g Group) error {
+		location := root
+		for _, p := range path {
+			p = strings.ToLower(p)
+			next, ok := location[p].(map[string]interface{})
+			if !ok {
+				next = make(map[string]interface{})
+				location[p] = next
+			}
+			location = next
+		}
+		for _, s := range g.Settings() {
+			v, _ := redactedValue(s)
+			if text, ok := formatTime(s, v); ok {
+				location[strings.ToLower(s.Name())] = text
+				continue
+			}
+			location[strings.ToLower(s.Name())] = dumpValue(v)
+		}
+		return nil
+	})
+	return root
+}
+
+// DumpYamlGroups renders the current values of all settings in the given
+// groups as YAML. Unlike ExampleYamlGroups, the output contains no comments
+// and the values of sensitive settings are redacted.
+func DumpYamlGroups(groups []Group) (string, error) {
+	b, err := yaml.Marshal(dumpTree(groups))
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// DumpJSONGroups renders the current values of all settings in the given
+// groups as JSON. The values of sensitive settings are redacted.
+func DumpJSONGroups(groups []Group) (string, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(dumpTree(groups)); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// DumpEnvGroups renders the current values of all settings in the given
+// groups as ENV vars. The values of sensitive settings are redacted.
+func DumpEnvGroups(groups []Group) string {
+	var b bytes.Buffer
+	_ = walkGroups(groups, func(path []string, g Group) error {
+		prefix := strings.ToUpper(strings.Join(path, "_"))
+		for _, s := range g.Settings() {
+			v, redacted := redactedValue(s)
+			display := fmt.Sprintf("%q", v)
+			if !redacted {
+				display = envTypeDisplaySeparated(v, separator(s))
+			}
+			if text, ok := formatTime(s, v); ok {
+				display = envQuote(text)
+			}
+			_, _ = b.WriteString(fmt.Sprintf("%s_%s=%s\n", prefix, strings.ToUpper(s.Name()), display))
+		}
+		return nil
+	})
+	return b.String()
+}
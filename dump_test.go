@@ -0,0 +1,91 @@
+package settings
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andreyvit/diff"
+)
+
+type dumpInner struct {
+	Token string `secret:"true"`
+	Empty string `secret:"true"`
+}
+
+type dumpConf struct {
+	Host    string
+	Timeout time.Duration
+	Ports   []int
+	Since   time.Time `layout:"DateOnly"`
+	Inner   *dumpInner
+}
+
+func dumpGroups(t *testing.T) []Group {
+	return convertGroups(t, &dumpConf{
+		Host:    "localhost",
+		Timeout: time.Second,
+		Ports:   []int{80, 443},
+		Since:   time.Date(2020, time.March, 1, 0, 0, 0, 0, time.UTC),
+		Inner:   &dumpInner{Token: "hunter2"},
+	})
+}
+
+func TestDumpYamlGroups(t *testing.T) {
+	want := `dumpconf:
+    dumpinner:
+        empty: ""
+        token: <redacted>
+    host: localhost
+    ports:
+        - 80
+        - 443
+    since: "2020-03-01"
+    timeout: 1s
+`
+	got, err := DumpYamlGroups(dumpGroups(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("DumpYamlGroups() = %v, want %v\n%s", got, want, diff.LineDiff(got, want))
+	}
+}
+
+func TestDumpJSONGroups(t *testing.T) {
+	want := `{
+  "dumpconf": {
+    "dumpinner": {
+      "empty": "",
+      "token": "<redacted>"
+    },
+    "host": "localhost",
+    "ports": [
+      80,
+      443
+    ],
+    "since": "2020-03-01",
+    "timeout": "1s"
+  }
+}
+`
+	got, err := DumpJSONGroups(dumpGroups(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("DumpJSONGroups() = %v, want %v\n%s", got, want, diff.LineDiff(got, want))
+	}
+}
+
+func TestDumpEnvGroups(t *testing.T) {
+	want := `DUMPCONF_SINCE="2020-03-01"
+DUMPCONF_PORTS="80 443"
+DUMPCONF_TIMEOUT="1s"
+DUMPCONF_HOST="localhost"
+DUMPCONF_DUMPINNER_EMPTY=""
+DUMPCONF_DUMPINNER_TOKEN="<redacted>"
+`
+	if got := DumpEnvGroups(dumpGroups(t)); got != want {
+		t.Errorf("DumpEnvGroups() = %v, want %v\n%s", got, want, diff.LineDiff(got, want))
+	}
+}
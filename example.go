@@ -3,41 +3,180 @@ package settings
 import (
 	"bufio"
 	"bytes"
+	"encoding"
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
 )
 
+func isTextMarshaler(t reflect.Type) bool {
+	return t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType)
+}
+
+// marshalText renders values that implement encoding.TextMarshaler either
+// directly or through a pointer.
+func marshalText(v interface{}) (string, bool) {
+	vv := reflect.ValueOf(v)
+	if !vv.IsValid() || !isTextMarshaler(vv.Type()) {
+		return "", false
+	}
+	if !vv.Type().Implements(textMarshalerType) {
+		ptr := reflect.New(vv.Type())
+		ptr.Elem().Set(vv)
+		vv = ptr
+	}
+	if vv.Kind() == reflect.Ptr && vv.IsNil() {
+		return "", true
+	}
+	b, err := vv.Interface().(encoding.TextMarshaler).MarshalText()
+	if err != nil {
+		return "", false
+	}
+	return string(b), true
+}
+
 func typeHint(v interface{}) string {
 	t := reflect.TypeOf(v)
+	if c, ok := lookupConverter(t); ok {
+		return c.TypeHint()
+	}
 	tn := t.String()
-	if t.Kind() == reflect.Slice {
+	if t.Kind() == reflect.Slice && !isTextMarshaler(t) {
 		tn = fmt.Sprintf(`[]%s`, t.Elem().String())
 	}
 	return tn
 }
 
+// sortedKeys returns the keys of a map ordered by their text form so that
+// rendered examples are stable.
+func sortedKeys(vv reflect.Value) []reflect.Value {
+	keys := vv.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+	})
+	return keys
+}
+
+// yamlQuote renders text as a double quoted YAML scalar. The escape
+// sequences produced by strconv.Quote are a subset of those YAML supports.
+func yamlQuote(s string) string {
+	return strconv.Quote(s)
+}
+
+// yamlPlainKey matches map keys that YAML reads back as the same string
+// without quoting.
+var yamlPlainKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_./-]*$`)
+
+// yamlKey renders a map key, quoting it if YAML would otherwise read it as
+// something other than the same string.
+func yamlKey(k string) string {
+	switch strings.ToLower(k) {
+	case "y", "n", "yes", "no", "on", "off", "true", "false", "null":
+		return yamlQuote(k)
+	default:
+	}
+	if !yamlPlainKey.MatchString(k) {
+		return yamlQuote(k)
+	}
+	return k
+}
+
 func yamlTypeDisplay(v interface{}) string {
+	if v == nil {
+		return "null"
+	}
 	t := reflect.TypeOf(v)
 	vv := reflect.ValueOf(v)
 	display := fmt.Sprintf("%v", v)
+	if t.String() == durationName || t.String() == timeName {
+		return yamlQuote(fmt.Sprintf("%s", v))
+	}
+	if c, ok := lookupConverter(t); ok {
+		return yamlQuote(c.Render(v))
+	}
+	if text, ok := marshalText(v); ok {
+		return yamlQuote(text)
+	}
+	if t.Kind() == reflect.Ptr {
+		if vv.IsNil() {
+			return "null"
+		}
+		return yamlTypeDisplay(vv.Elem().Interface())
+	}
 	if t.Kind() == reflect.Slice {
+		if vv.Len() < 1 {
+			// An empty block would be read back as null.
+			return "[]"
+		}
 		b := bytes.NewBufferString("\n")
 		for x := 0; x < vv.Len(); x = x + 1 {
 			b.WriteString(fmt.Sprintf("  - %s\n", yamlTypeDisplay(vv.Index(x).Interface())))
 		}
 		return b.String()
 	}
-	if t.Kind() == reflect.String {
-		return `"` + display + `"`
+	if t.Kind() == reflect.Map {
+		if vv.Len() < 1 {
+			return "{}"
+		}
+		b := bytes.NewBufferString("\n")
+		for _, k := range sortedKeys(vv) {
+			key := yamlKey(fmt.Sprint(k.Interface()))
+			d := yamlTypeDisplay(vv.MapIndex(k).Interface())
+			if d[0] != '\n' {
+				_, _ = b.WriteString(fmt.Sprintf("  %s: %s\n", key, d))
+				continue
+			}
+			// Nested blocks are indented beneath their key.
+			_, _ = b.WriteString(fmt.Sprintf("  %s:\n", key))
+			sc := bufio.NewScanner(strings.NewReader(d))
+			for sc.Scan() {
+				if sc.Text() != "" {
+					_, _ = b.WriteString("  " + sc.Text() + "\n")
+				}
+			}
+		}
+		return b.String()
 	}
-	if t.String() == durationName || t.String() == timeName {
-		return fmt.Sprintf("\"%s\"", v)
+	if t.Kind() == reflect.String {
+		return yamlQuote(display)
 	}
 	return display
 }
 
+// formatTime renders the value of a time setting that has layouts using the
+// first of them so that the output parses with the same setting. The time
+// is converted to the location of the setting first because layouts without
+// a zone are parsed in that location. Pointers and Optionals are rendered
+// with the layouts of their element.
+func formatTime(s Setting, v interface{}) (string, bool) {
+	tf, ok := elementOf(s).(TimeFormatted)
+	if p, isPointer := v.(*time.Time); isPointer && p != nil {
+		v = *p
+	}
+	t, isTime := v.(time.Time)
+	if !ok || !isTime || len(tf.Layouts()) < 1 {
+		return "", false
+	}
+	return t.In(tf.Location()).Format(tf.Layouts()[0]), true
+}
+
+// exampleValue returns the value of a setting for use in an example. The
+// value of a sensitive setting is replaced by the zero value of its type so
+// that secret defaults are never rendered.
+func exampleValue(s Setting) interface{} {
+	v := s.Value()
+	if v == nil || !isSensitive(s) {
+		return v
+	}
+	return reflect.Zero(reflect.TypeOf(v)).Interface()
+}
+
 func removeExtraLines(s string) string {
 	var b bytes.Buffer
 	scn := bufio.NewScanner(strings.NewReader(s))
@@ -83,8 +222,14 @@ func ExampleYamlSettings(settings []Setting) string {
 	var b bytes.Buffer
 	for _, setting := range settings {
 		hint := typeHint(setting.Value())
-		display := yamlTypeDisplay(setting.Value())
+		display := yamlTypeDisplay(exampleValue(setting))
+		if text, ok := formatTime(setting, exampleValue(setting)); ok {
+			display = yamlQuote(text)
+		}
 		_, _ = b.WriteString(fmt.Sprintf("# (%s) %s\n", hint, setting.Description()))
+		if isUnset(setting) {
+			continue
+		}
 		displayName := strings.ToLower(setting.Name())
 		if display[0] == '\n' {
 			// Special case for things that appear on the next line so we can
@@ -98,29 +243,150 @@ func ExampleYamlSettings(settings []Setting) string {
 }
 
 func envTypeDisplay(v interface{}) string {
+	return envTypeDisplaySeparated(v, " ")
+}
+
+// separator returns the string used to join the elements of a slice
+// setting when it is rendered as a single ENV value.
+func separator(s Setting) string {
+	if sp, ok := elementOf(s).(Separated); ok && sp.Separator() != "" {
+		return sp.Separator()
+	}
+	return " "
+}
+
+func envTypeDisplaySeparated(v interface{}, sep string) string {
+	return envQuote(envText(v, sep))
+}
+
+// envValue renders a value of the setting as the text of an ENV var.
+func envValue(s Setting, v interface{}) string {
+	if text, ok := formatTime(s, v); ok {
+		return text
+	}
+	return envText(v, separator(s))
+}
+
+// envQuote renders text as a double quoted shell word so that the shell
+// passes it to the program unchanged.
+var envQuote = func() func(string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "`", "\\`")
+	return func(s string) string {
+		return `"` + r.Replace(s) + `"`
+	}
+}()
+
+// envText renders a value as the text of an ENV var. Slices are joined
+// with the separator and maps are rendered as "k1=v1,k2=v2" unless that
+// would change the value when it is parsed, in which case they are
+// rendered as JSON.
+func envText(v interface{}, sep string) string {
+	if v == nil {
+		return ""
+	}
 	t := reflect.TypeOf(v)
 	vv := reflect.ValueOf(v)
-	display := fmt.Sprintf(`"%v"`, v)
-	if t.Kind() == reflect.Slice {
-		b := bytes.NewBufferString(`"`)
-		for x := 0; x < vv.Len()-1; x = x + 1 {
-			d := envTypeDisplay(vv.Index(x).Interface())
-			_, _ = b.WriteString(fmt.Sprintf("%s ", strings.Trim(d, `"`)))
+	if t.String() == durationName {
+		return fmt.Sprintf("%s", v)
+	}
+	if t.String() == timeName {
+		return vv.Interface().(time.Time).Format(time.RFC3339Nano)
+	}
+	if c, ok := lookupConverter(t); ok {
+		return c.Render(v)
+	}
+	if text, ok := marshalText(v); ok {
+		return text
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		if vv.IsNil() {
+			return ""
 		}
-		if vv.Len() > 0 {
-			d := envTypeDisplay(vv.Index(vv.Len() - 1).Interface())
-			_, _ = b.WriteString(strings.Trim(d, `"`))
+		return envText(vv.Elem().Interface(), sep)
+	case reflect.Slice:
+		return envListText(vv, sep)
+	case reflect.Map:
+		return envMapText(vv)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// jsonText renders a value as compact JSON in the same form as a dump.
+func jsonText(v interface{}) string {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(dumpValue(v)); err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// isPlainElement returns true if text survives being joined with the
+// separator and split again.
+func isPlainElement(text string, sep string) bool {
+	if text == "" || strings.TrimSpace(text) != text {
+		return false
+	}
+	if strings.TrimSpace(sep) == "" {
+		return strings.IndexFunc(text, unicode.IsSpace) < 0
+	}
+	return !strings.Contains(text, sep)
+}
+
+func envListText(vv reflect.Value, sep string) string {
+	parts := make([]string, 0, vv.Len())
+	for x := 0; x < vv.Len(); x = x + 1 {
+		text := envText(vv.Index(x).Interface(), " ")
+		if !isPlainElement(text, sep) {
+			return jsonText(vv.Interface())
 		}
-		_, _ = b.WriteString(`"`)
-		return b.String()
+		parts = append(parts, text)
 	}
-	if t.String() == "time.Duration" {
-		return fmt.Sprintf(`"%s"`, v)
+	text := strings.Join(parts, sep)
+	if isJSONList(text) {
+		return jsonText(vv.Interface())
 	}
-	if t.String() == timeName {
-		return `"` + vv.Interface().(time.Time).Format(time.RFC3339Nano) + `"`
+	return text
+}
+
+func envMapText(vv reflect.Value) string {
+	pairs := make([]string, 0, vv.Len())
+	for _, k := range sortedKeys(vv) {
+		key := fmt.Sprint(k.Interface())
+		value := vv.MapIndex(k)
+		if value.Kind() == reflect.Interface {
+			value = value.Elem()
+		}
+		if value.Kind() == reflect.Slice || value.Kind() == reflect.Map {
+			return jsonText(vv.Interface())
+		}
+		if vv.Type().Elem().Kind() == reflect.Interface && value.Kind() != reflect.String {
+			// Pairs are parsed as strings so other types need JSON.
+			return jsonText(vv.Interface())
+		}
+		var text string
+		if value.IsValid() {
+			text = envText(value.Interface(), " ")
+		}
+		if !isPlainElement(key, ",") || strings.Contains(key, "=") ||
+			strings.TrimSpace(text) != text || strings.Contains(text, ",") {
+			return jsonText(vv.Interface())
+		}
+		pairs = append(pairs, key+"="+text)
 	}
-	return display
+	text := strings.Join(pairs, ",")
+	if strings.HasPrefix(text, "{") {
+		return jsonText(vv.Interface())
+	}
+	return text
+}
+
+// envName returns the ENV var name for a path of group and setting names.
+func envName(path ...string) string {
+	return strings.ToUpper(strings.Join(path, "_"))
 }
 
 // ExampleEnvGroups renders a Group to ENV vars.
@@ -137,7 +403,7 @@ func ExampleEnvGroups(groups []Group) string {
 			// env var name prefix for nexted groups with each group
 			// still rendering individual settings with the right prefix.
 			cpy := &SettingGroup{
-				NameValue: strings.ToUpper(current.Name() + "_" + g.Name()),
+				NameValue: envName(current.Name(), g.Name()),
 			}
 			if len(g.Settings()) > 0 {
 				cpy.SettingValues = make([]Setting, len(g.Settings()))
@@ -156,7 +422,7 @@ func ExampleEnvGroups(groups []Group) string {
 				_, _ = b.WriteString(sc.Text() + "\n")
 				continue
 			}
-			_, _ = b.WriteString(strings.ToUpper(current.Name()) + "_" + sc.Text() + "\n")
+			_, _ = b.WriteString(envName(current.Name()) + "_" + sc.Text() + "\n")
 		}
 	}
 	return removeExtraLines(b.String())
@@ -167,9 +433,110 @@ func ExampleEnvSettings(settings []Setting) string {
 	var b bytes.Buffer
 	for _, setting := range settings {
 		hint := typeHint(setting.Value())
-		display := envTypeDisplay(setting.Value())
+		display := envQuote(envValue(setting, exampleValue(setting)))
 		_, _ = b.WriteString(fmt.Sprintf("# (%s) %s\n", hint, setting.Description()))
-		_, _ = b.WriteString(fmt.Sprintf("%s=%s\n", strings.ToUpper(setting.Name()), display))
+		if isUnset(setting) {
+			continue
+		}
+		_, _ = b.WriteString(fmt.Sprintf("%s=%s\n", envName(setting.Name()), display))
 	}
 	return removeExtraLines(b.String())
 }
+
+// jsonCommentKey is the key used for descriptions in JSON examples. It is
+// borrowed from JSON Schema and is ignored when checking for unknown keys.
+const jsonCommentKey = "$comment"
+
+type exampleJSONOptions struct {
+	comments bool
+}
+
+// ExampleJSONOption modifies the output of ExampleJSONGroups and
+// ExampleJSONSettings.
+type ExampleJSONOption func(*exampleJSONOptions)
+
+// WithJSONComments adds a "$comment" list to every object that contains
+// the description of the group followed by the type and description of each
+// setting. JSON has no comments so this is the only way to carry the same
+// information as the YAML and ENV examples. Alternatively, JSONSchemaGroups
+// renders the descriptions as a separate document.
+func WithJSONComments() ExampleJSONOption {
+	return func(o *exampleJSONOptions) {
+		o.comments = true
+	}
+}
+
+func newExampleJSONOptions(opts []ExampleJSONOption) *exampleJSONOptions {
+	o := &exampleJSONOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// exampleJSONObject renders settings into a map keyed by the lower case
+// names used for lookups.
+func exampleJSONObject(description string, settings []Setting, o *exampleJSONOptions) map[string]interface{} {
+	result := make(map[string]interface{}, len(settings))
+	var comments []string
+	if description != "" {
+		comments = append(comments, description)
+	}
+	for _, setting := range settings {
+		name := strings.ToLower(setting.Name())
+		comments = append(comments, strings.TrimSpace(fmt.Sprintf("%s (%s) %s", name, typeHint(setting.Value()), setting.Description())))
+		if isUnset(setting) {
+			continue
+		}
+		v := exampleValue(setting)
+		if text, ok := formatTime(setting, v); ok {
+			result[name] = text
+			continue
+		}
+		result[name] = dumpValue(v)
+	}
+	if o.comments && len(comments) > 0 {
+		result[jsonCommentKey] = comments
+	}
+	return result
+}
+
+func exampleJSONTree(groups []Group, o *exampleJSONOptions) map[string]interface{} {
+	result := make(map[string]interface{}, len(groups))
+	for _, g := range groups {
+		if len(g.Settings()) < 1 && len(g.Groups()) < 1 {
+			// Skip empty sections entirely.
+			continue
+		}
+		obj := exampleJSONObject(g.Description(), g.Settings(), o)
+		for k, v := range exampleJSONTree(g.Groups(), o) {
+			obj[k] = v
+		}
+		result[strings.ToLower(g.Name())] = obj
+	}
+	return result
+}
+
+func encodeExampleJSON(v interface{}) (string, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// ExampleJSONGroups renders a Group to JSON. Unlike the other examples, the
+// output is a complete document that NewJSONSource can parse. Values keep
+// their native JSON types except for those that are parsed from text, such
+// as durations and times, which are strings.
+func ExampleJSONGroups(groups []Group, opts ...ExampleJSONOption) (string, error) {
+	return encodeExampleJSON(exampleJSONTree(groups, newExampleJSONOptions(opts)))
+}
+
+// ExampleJSONSettings renders a collection of settings as a JSON object.
+func ExampleJSONSettings(settings []Setting, opts ...ExampleJSONOption) (string, error) {
+	return encodeExampleJSON(exampleJSONObject("", settings, newExampleJSONOptions(opts)))
+}
@@ -1,6 +1,8 @@
 package settings
 
 import (
+	"context"
+	"net"
 	"testing"
 	"time"
 
@@ -58,6 +60,21 @@ func Test_typeHint(t *testing.T) {
 			v:    []time.Time{time.Now()},
 			want: "[]time.Time",
 		},
+		{
+			name: "float64 slice",
+			v:    []float64{1.5},
+			want: "[]float64",
+		},
+		{
+			name: "text marshaler slice",
+			v:    net.IPv4(127, 0, 0, 1),
+			want: "net.IP",
+		},
+		{
+			name: "text marshaler",
+			v:    testLevel(1),
+			want: "settings.testLevel",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -104,6 +121,31 @@ func Test_yamlTypeDisplay(t *testing.T) {
 			v:    time.Date(1999, time.January, 1, 0, 0, 0, 0, time.UTC),
 			want: `"1999-01-01 00:00:00 +0000 UTC"`,
 		},
+		{
+			name: "string with quotes",
+			v:    `say "hi" \ bye`,
+			want: `"say \"hi\" \\ bye"`,
+		},
+		{
+			name: "string with newline",
+			v:    "one\ntwo",
+			want: `"one\ntwo"`,
+		},
+		{
+			name: "empty slice",
+			v:    []string{},
+			want: "[]",
+		},
+		{
+			name: "empty map",
+			v:    map[string]int{},
+			want: "{}",
+		},
+		{
+			name: "map with keys that need quotes",
+			v:    map[string]int{"a: b": 1, "true": 2, "plain": 3},
+			want: "\n  \"a: b\": 1\n  plain: 3\n  \"true\": 2\n",
+		},
 		{
 			name: "string slice",
 			v:    []string{"value1", "value2"},
@@ -122,6 +164,51 @@ func Test_yamlTypeDisplay(t *testing.T) {
 			},
 			want: "\n  - \"1999-01-01 00:00:00 +0000 UTC\"\n  - \"2000-01-01 00:00:00 +0000 UTC\"\n",
 		},
+		{
+			name: "float64 slice",
+			v:    []float64{1.5, 2},
+			want: "\n  - 1.5\n  - 2\n",
+		},
+		{
+			name: "text marshaler slice",
+			v:    net.IPv4(127, 0, 0, 1),
+			want: `"127.0.0.1"`,
+		},
+		{
+			name: "text marshaler",
+			v:    testLevel(1),
+			want: `"debug"`,
+		},
+		{
+			name: "text marshaler pointer",
+			v:    func() *testLevel { l := testLevel(1); return &l }(),
+			want: `"debug"`,
+		},
+		{
+			name: "byte size",
+			v:    512 * Kibibyte,
+			want: `"512KiB"`,
+		},
+		{
+			name: "nil pointer",
+			v:    (*int)(nil),
+			want: "null",
+		},
+		{
+			name: "pointer",
+			v:    func() *int { v := 1; return &v }(),
+			want: "1",
+		},
+		{
+			name: "map",
+			v:    map[string]int{"b": 2, "a": 1},
+			want: "\n  a: 1\n  b: 2\n",
+		},
+		{
+			name: "map of slices",
+			v:    map[string][]string{"a": {"x", "y"}},
+			want: "\n  a:\n    - \"x\"\n    - \"y\"\n",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -158,6 +245,30 @@ when: "1999-01-01 00:00:00 +0000 UTC"
 what:
   - "one"
   - "two"
+`,
+		},
+		{
+			name: "layout",
+			settings: []Setting{
+				NewLayoutTimeSetting("when", "when does it happen?",
+					time.Date(1999, time.January, 1, 0, 0, 0, 0, time.UTC), time.DateOnly),
+			},
+			want: `# (time.Time) when does it happen?
+when: "1999-01-01"
+`,
+		},
+		{
+			name: "layout in location",
+			settings: []Setting{
+				func() Setting {
+					s := NewLayoutTimeSetting("when", "when does it happen?",
+						time.Date(2020, time.February, 29, 15, 0, 0, 0, time.UTC), time.DateOnly)
+					s.LocationValue, _ = time.LoadLocation("Asia/Tokyo")
+					return s
+				}(),
+			},
+			want: `# (time.Time) when does it happen?
+when: "2020-03-01"
 `,
 		},
 	}
@@ -323,6 +434,36 @@ func Test_envTypeDisplay(t *testing.T) {
 			v:    time.Date(1999, time.January, 1, 0, 0, 0, 0, time.UTC),
 			want: `"1999-01-01T00:00:00Z"`,
 		},
+		{
+			name: "string with shell characters",
+			v:    "say \"hi\" to $USER `now` \\",
+			want: "\"say \\\"hi\\\" to \\$USER \\`now\\` \\\\\"",
+		},
+		{
+			name: "string slice with spaces",
+			v:    []string{"two words", "one"},
+			want: `"[\"two words\",\"one\"]"`,
+		},
+		{
+			name: "string slice with empty element",
+			v:    []string{"", "one"},
+			want: `"[\"\",\"one\"]"`,
+		},
+		{
+			name: "string slice that looks like JSON",
+			v:    []string{"[one]"},
+			want: `"[\"[one]\"]"`,
+		},
+		{
+			name: "map with separators",
+			v:    map[string]string{"a": "b,c"},
+			want: `"{\"a\":\"b,c\"}"`,
+		},
+		{
+			name: "map of slices",
+			v:    map[string][]string{"a": {"b"}},
+			want: `"{\"a\":[\"b\"]}"`,
+		},
 		{
 			name: "string slice",
 			v:    []string{"value1", "value2"},
@@ -341,6 +482,31 @@ func Test_envTypeDisplay(t *testing.T) {
 			},
 			want: `"1999-01-01T00:00:00Z 2000-01-01T00:00:00Z"`,
 		},
+		{
+			name: "text marshaler slice",
+			v:    net.IPv4(127, 0, 0, 1),
+			want: `"127.0.0.1"`,
+		},
+		{
+			name: "text marshaler",
+			v:    testLevel(1),
+			want: `"debug"`,
+		},
+		{
+			name: "byte size",
+			v:    10 * Megabyte,
+			want: `"10MB"`,
+		},
+		{
+			name: "nil pointer",
+			v:    (*int)(nil),
+			want: `""`,
+		},
+		{
+			name: "map",
+			v:    map[string]time.Duration{"b": time.Minute, "a": time.Second},
+			want: `"a=1s,b=1m0s"`,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -375,6 +541,29 @@ ENABLED="false"
 WHEN="1999-01-01T00:00:00Z"
 # ([]string) do something with these
 WHAT="one two"
+`,
+		},
+		{
+			name: "separated",
+			settings: []Setting{
+				func() Setting {
+					s := NewStringSliceSetting("what", "do something with these", []string{"one", "two"})
+					s.SeparatorValue = ","
+					return s
+				}(),
+			},
+			want: `# ([]string) do something with these
+WHAT="one,two"
+`,
+		},
+		{
+			name: "layout",
+			settings: []Setting{
+				NewLayoutTimeSetting("when", "when does it happen?",
+					time.Date(1999, time.January, 1, 0, 0, 0, 0, time.UTC), time.DateOnly),
+			},
+			want: `# (time.Time) when does it happen?
+WHEN="1999-01-01"
 `,
 		},
 	}
@@ -488,3 +677,100 @@ OUTER_INNER1_WHAT="one two"
 		})
 	}
 }
+
+func TestExampleJSONSettings(t *testing.T) {
+	settings := []Setting{
+		NewBoolSetting("enabled", "is it on?", true),
+		NewIntSetting("count", "how many?", 3),
+		NewDurationSetting("timeout", "how long?", time.Second),
+		NewTimeSetting("when", "when does it happen?", time.Date(1999, time.January, 1, 0, 0, 0, 0, time.UTC)),
+		NewStringSliceSetting("what", "do something with these", []string{"one", "two"}),
+		NewSecretSetting("password", "the password", "hunter2"),
+	}
+	want := `{
+  "count": 3,
+  "enabled": true,
+  "password": "",
+  "timeout": "1s",
+  "what": [
+    "one",
+    "two"
+  ],
+  "when": "1999-01-01T00:00:00Z"
+}
+`
+	got, err := ExampleJSONSettings(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("ExampleJSONSettings() = %v, want %v\n%s", got, want, diff.LineDiff(got, want))
+	}
+}
+
+func TestExampleJSONGroups(t *testing.T) {
+	groups := []Group{
+		&SettingGroup{
+			NameValue:        "outer",
+			DescriptionValue: "the outer group",
+			SettingValues: []Setting{
+				NewIntSetting("count", "how many?", 3),
+			},
+			GroupValues: []Group{
+				&SettingGroup{
+					NameValue: "Inner",
+					SettingValues: []Setting{
+						NewDurationSetting("timeout", "how long?", time.Second),
+						NewLayoutTimeSetting("since", "", time.Date(2020, time.March, 1, 0, 0, 0, 0, time.UTC), time.DateOnly),
+					},
+				},
+				&SettingGroup{NameValue: "empty"},
+			},
+		},
+	}
+	want := `{
+  "outer": {
+    "$comment": [
+      "the outer group",
+      "count (int) how many?"
+    ],
+    "count": 3,
+    "inner": {
+      "$comment": [
+        "timeout (time.Duration) how long?",
+        "since (time.Time)"
+      ],
+      "since": "2020-03-01",
+      "timeout": "1s"
+    }
+  }
+}
+`
+	got, err := ExampleJSONGroups(groups, WithJSONComments())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("ExampleJSONGroups() = %v, want %v\n%s", got, want, diff.LineDiff(got, want))
+	}
+
+	// The example must load back into the same groups without any unknown
+	// keys, including the comments.
+	s, err := NewJSONSource([]byte(got))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unknown := UnknownKeys(s, groups); len(unknown) > 0 {
+		t.Errorf("UnknownKeys() = %v, want none", unknown)
+	}
+	if err := LoadGroups(context.Background(), s, groups); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := ExampleJSONGroups(groups, WithJSONComments())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloaded != got {
+		t.Errorf("ExampleJSONGroups() after loading = %v, want %v\n%s", reloaded, got, diff.LineDiff(reloaded, got))
+	}
+}
@@ -0,0 +1,206 @@
+package settings
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// kubernetesObject contains the fields of a ConfigMap or Secret in the order
+// they are conventionally written.
+type kubernetesObject struct {
+	APIVersion string             `yaml:"apiVersion"`
+	Kind       string             `yaml:"kind"`
+	Metadata   kubernetesMetadata `yaml:"metadata"`
+	Type       string             `yaml:"type,omitempty"`
+	Data       map[string]string  `yaml:"data,omitempty"`
+	StringData map[string]string  `yaml:"stringData,omitempty"`
+}
+
+type kubernetesMetadata struct {
+	Name string `yaml:"name"`
+}
+
+// envSetting is a setting paired with the name of its ENV var.
+type envSetting struct {
+	name    string
+	path    []string
+	setting Setting
+}
+
+// envSettings lists every setting in the given groups with the ENV var
+// name that NewEnvSource maps to it. Nil pointers and unset Optionals are
+// left out because any value rendered for them would set them when loaded.
+func envSettings(groups []Group) []envSetting {
+	var result []envSetting
+	_ = walkGroups(groups, func(path []string, g Group) error {
+		for _, s := range g.Settings() {
+			if isUnset(s) {
+				continue
+			}
+			settingPath := append(append(make([]string, 0, len(path)+1), path...), s.Name())
+			result = append(result, envSetting{name: envName(settingPath...), path: settingPath, setting: s})
+		}
+		return nil
+	})
+	return result
+}
+
+func encodeYAML(v interface{}) (string, error) {
+	var b bytes.Buffer
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+	if err := enc.Close(); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// KubernetesConfigMap renders a ConfigMap manifest with the given name that
+// contains an entry for every setting that is not sensitive and has a
+// value, so nil pointers and unset Optionals are left out. Each key is the
+// name of the ENV var that NewEnvSource reads and each value is the current
+// value of the setting in the same form as ExampleEnvGroups. The ConfigMap
+// may be given to a container with envFrom.
+func KubernetesConfigMap(name string, groups []Group) (string, error) {
+	data := make(map[string]string)
+	for _, e := range envSettings(groups) {
+		if isSensitive(e.setting) {
+			continue
+		}
+		data[e.name] = envValue(e.setting, e.setting.Value())
+	}
+	return encodeYAML(kubernetesObject{
+		APIVersion: "v1",
+		Kind:       "ConfigMap",
+		Metadata:   kubernetesMetadata{Name: name},
+		Data:       data,
+	})
+}
+
+// KubernetesSecret renders a Secret manifest with the given name that
+// contains an empty entry for every sensitive setting. Secret values are
+// never rendered so the output is a template to be filled in by whatever
+// manages secrets for the cluster. Keys follow the same rules as
+// KubernetesConfigMap.
+func KubernetesSecret(name string, groups []Group) (string, error) {
+	data := make(map[string]string)
+	for _, e := range envSettings(groups) {
+		if isSensitive(e.setting) {
+			data[e.name] = ""
+		}
+	}
+	return encodeYAML(kubernetesObject{
+		APIVersion: "v1",
+		Kind:       "Secret",
+		Metadata:   kubernetesMetadata{Name: name},
+		Type:       "Opaque",
+		StringData: data,
+	})
+}
+
+// HelmValues renders a values.yaml document that contains the current
+// value of every setting that is not sensitive and has a value. Values are nested by the
+// lower case names of their groups, like DumpYamlGroups, so that they can be
+// referenced by the template rendered with HelmEnv.
+func HelmValues(groups []Group) (string, error) {
+	root := make(map[string]interface{})
+	for _, e := range envSettings(groups) {
+		if isSensitive(e.setting) {
+			continue
+		}
+		location := root
+		for _, p := range e.path[:len(e.path)-1] {
+			p = strings.ToLower(p)
+			next, ok := location[p].(map[string]interface{})
+			if !ok {
+				next = make(map[string]interface{})
+				location[p] = next
+			}
+			location = next
+		}
+		v := e.setting.Value()
+		key := strings.ToLower(e.setting.Name())
+		if text, ok := formatTime(e.setting, v); ok {
+			location[key] = text
+			continue
+		}
+		location[key] = dumpValue(v)
+	}
+	return encodeYAML(root)
+}
+
+// helmIdentifier matches keys that may be used in a dotted Helm reference.
+var helmIdentifier = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// helmReference renders a template reference to a value in values.yaml.
+func helmReference(path []string) string {
+	dotted := true
+	quoted := make([]string, 0, len(path))
+	for _, p := range path {
+		p = strings.ToLower(p)
+		dotted = dotted && helmIdentifier.MatchString(p)
+		quoted = append(quoted, fmt.Sprintf("%q", p))
+	}
+	if dotted {
+		return ".Values." + strings.ToLower(strings.Join(path, "."))
+	}
+	return fmt.Sprintf("(index .Values %s)", strings.Join(quoted, " "))
+}
+
+// helmPipeline renders the template functions that convert a value from
+// values.yaml into the text that the setting parses. Lists and maps become
+// JSON, which slice and map settings accept from ENV. Integers are
+// converted explicitly because Helm reads every number as a float.
+func helmPipeline(v interface{}) string {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch {
+	case t == nil:
+		return "quote"
+	case hasConverter(t), t == durationType, t == timeType, isTextType(t), isTextMarshaler(t):
+		return "quote"
+	default:
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return "toJson | quote"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "int64 | quote"
+	default:
+		return "quote"
+	}
+}
+
+// HelmEnv renders the env block of a container for a Helm template. Nil
+// pointers and unset Optionals are left out. Every other setting that is
+// not sensitive reads its value from the document rendered by HelmValues. Every sensitive setting reads its value from the Secret
+// with the given name, such as one created from KubernetesSecret.
+func HelmEnv(secretName string, groups []Group) string {
+	var b bytes.Buffer
+	_, _ = b.WriteString("env:\n")
+	for _, e := range envSettings(groups) {
+		_, _ = b.WriteString(fmt.Sprintf("  - name: %s\n", e.name))
+		if isSensitive(e.setting) {
+			_, _ = b.WriteString("    valueFrom:\n")
+			_, _ = b.WriteString("      secretKeyRef:\n")
+			_, _ = b.WriteString(fmt.Sprintf("        name: %s\n", secretName))
+			_, _ = b.WriteString(fmt.Sprintf("        key: %s\n", e.name))
+			continue
+		}
+		_, _ = b.WriteString(fmt.Sprintf(
+			"    value: {{ %s | %s }}\n", helmReference(e.path), helmPipeline(e.setting.Value()),
+		))
+	}
+	return b.String()
+}
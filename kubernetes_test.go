@@ -0,0 +1,212 @@
+package settings
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/andreyvit/diff"
+	"gopkg.in/yaml.v3"
+)
+
+type kubernetesDB struct {
+	Password Secret
+	Token    string `secret:"true"`
+	Hosts    []string
+}
+
+type kubernetesConf struct {
+	Name    string
+	Port    int
+	Timeout time.Duration
+	Labels  map[string]string
+	DB      *kubernetesDB
+}
+
+func newKubernetesConf() *kubernetesConf {
+	return &kubernetesConf{
+		Name:    "my app",
+		Port:    8080,
+		Timeout: time.Minute,
+		Labels:  map[string]string{"team": "core"},
+		DB:      &kubernetesDB{Password: "hunter2", Token: "abc", Hosts: []string{"db-1", "db 2"}},
+	}
+}
+
+func TestKubernetesConfigMap(t *testing.T) {
+	want := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+data:
+  KUBERNETESCONF_KUBERNETESDB_HOSTS: '["db-1","db 2"]'
+  KUBERNETESCONF_LABELS: team=core
+  KUBERNETESCONF_NAME: my app
+  KUBERNETESCONF_PORT: "8080"
+  KUBERNETESCONF_TIMEOUT: 1m0s
+`
+	got, err := KubernetesConfigMap("app-config", convertGroups(t, newKubernetesConf()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("KubernetesConfigMap() = %v, want %v\n%s", got, want, diff.LineDiff(got, want))
+	}
+
+	// The data must load back into the same values through NewEnvSource.
+	var cm kubernetesObject
+	if err := yaml.Unmarshal([]byte(got), &cm); err != nil {
+		t.Fatal(err)
+	}
+	env := make([]string, 0, len(cm.Data))
+	for k, v := range cm.Data {
+		env = append(env, k+"="+v)
+	}
+	s, err := NewEnvSource(env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := &kubernetesConf{DB: &kubernetesDB{}}
+	if err := LoadGroups(context.Background(), s, convertGroups(t, loaded)); err != nil {
+		t.Fatal(err)
+	}
+	expected := newKubernetesConf()
+	expected.DB.Password = ""
+	expected.DB.Token = ""
+	if !reflect.DeepEqual(loaded, expected) {
+		t.Errorf("loaded ConfigMap = %#v, want %#v", loaded, expected)
+	}
+}
+
+func TestKubernetesSecret(t *testing.T) {
+	want := `apiVersion: v1
+kind: Secret
+metadata:
+  name: app-secrets
+type: Opaque
+stringData:
+  KUBERNETESCONF_KUBERNETESDB_PASSWORD: ""
+  KUBERNETESCONF_KUBERNETESDB_TOKEN: ""
+`
+	got, err := KubernetesSecret("app-secrets", convertGroups(t, newKubernetesConf()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("KubernetesSecret() = %v, want %v\n%s", got, want, diff.LineDiff(got, want))
+	}
+}
+
+func TestHelmValues(t *testing.T) {
+	want := `kubernetesconf:
+  kubernetesdb:
+    hosts:
+      - db-1
+      - db 2
+  labels:
+    team: core
+  name: my app
+  port: 8080
+  timeout: 1m0s
+`
+	got, err := HelmValues(convertGroups(t, newKubernetesConf()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("HelmValues() = %v, want %v\n%s", got, want, diff.LineDiff(got, want))
+	}
+}
+
+func TestHelmEnv(t *testing.T) {
+	groups := convertGroups(t, newKubernetesConf())
+	groups = append(groups, &SettingGroup{
+		NameValue:     "my-app",
+		SettingValues: []Setting{NewStringSetting("mode", "", "fast")},
+	})
+	want := `env:
+  - name: KUBERNETESCONF_LABELS
+    value: {{ .Values.kubernetesconf.labels | toJson | quote }}
+  - name: KUBERNETESCONF_TIMEOUT
+    value: {{ .Values.kubernetesconf.timeout | quote }}
+  - name: KUBERNETESCONF_PORT
+    value: {{ .Values.kubernetesconf.port | int64 | quote }}
+  - name: KUBERNETESCONF_NAME
+    value: {{ .Values.kubernetesconf.name | quote }}
+  - name: KUBERNETESCONF_KUBERNETESDB_HOSTS
+    value: {{ .Values.kubernetesconf.kubernetesdb.hosts | toJson | quote }}
+  - name: KUBERNETESCONF_KUBERNETESDB_TOKEN
+    valueFrom:
+      secretKeyRef:
+        name: app-secrets
+        key: KUBERNETESCONF_KUBERNETESDB_TOKEN
+  - name: KUBERNETESCONF_KUBERNETESDB_PASSWORD
+    valueFrom:
+      secretKeyRef:
+        name: app-secrets
+        key: KUBERNETESCONF_KUBERNETESDB_PASSWORD
+  - name: MY-APP_MODE
+    value: {{ (index .Values "my-app" "mode") | quote }}
+`
+	if got := HelmEnv("app-secrets", groups); got != want {
+		t.Errorf("HelmEnv() = %v, want %v\n%s", got, want, diff.LineDiff(got, want))
+	}
+}
+
+type kubernetesUnsetConf struct {
+	Name     string
+	Replicas *int
+	Level    Optional[int]
+}
+
+func TestKubernetes_unset(t *testing.T) {
+	groups := convertGroups(t, &kubernetesUnsetConf{Name: "app"})
+	configMap, err := KubernetesConfigMap("app-config", groups)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values, err := HelmValues(groups)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{
+			name: "ConfigMap",
+			got:  configMap,
+			want: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+data:
+  KUBERNETESUNSETCONF_NAME: app
+`,
+		},
+		{
+			name: "HelmValues",
+			got:  values,
+			want: `kubernetesunsetconf:
+  name: app
+`,
+		},
+		{
+			name: "HelmEnv",
+			got:  HelmEnv("app-secrets", groups),
+			want: `env:
+  - name: KUBERNETESUNSETCONF_NAME
+    value: {{ .Values.kubernetesunsetconf.name | quote }}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("%s = %v, want %v\n%s", tt.name, tt.got, tt.want, diff.LineDiff(tt.got, tt.want))
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package settings
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+)
+
+// Starter is an optional interface for components that must do work, such
+// as opening connections or starting background routines, before use.
+type Starter interface {
+	Start(ctx context.Context) error
+}
+
+// Lifecycle starts and closes a collection of component values. Values are
+// started in the order they were added and closed in the reverse order so
+// that a value is always closed before the values it was built from. Values
+// that implement neither Starter nor io.Closer are ignored.
+type Lifecycle struct {
+	lock   sync.Mutex
+	values []interface{}
+	// failed is the index of the value whose Start failed, if any. That
+	// value is not closed because it never started.
+	failed int
+	// hasFailed is true when failed holds an index.
+	hasFailed bool
+}
+
+// Add a value to the end of the Lifecycle.
+func (l *Lifecycle) Add(v interface{}) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.values = append(l.values, v)
+}
+
+func (l *Lifecycle) snapshot() []interface{} {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	values := make([]interface{}, len(l.values))
+	copy(values, l.values)
+	return values
+}
+
+// Start calls Start on every value in order. Starting stops at the first
+// error because later values may rely on those before them. Close should
+// be called to release any values that were started.
+func (l *Lifecycle) Start(ctx context.Context) error {
+	l.setFailed(0, false)
+	for x, v := range l.snapshot() {
+		s, ok := v.(Starter)
+		if !ok || isNilValue(v) {
+			continue
+		}
+		if err := s.Start(ctx); err != nil {
+			l.setFailed(x, true)
+			return fmt.Errorf("failed to start %T due to: %s", v, err.Error())
+		}
+	}
+	return nil
+}
+
+func (l *Lifecycle) setFailed(x int, failed bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.failed, l.hasFailed = x, failed
+}
+
+// Close calls Close on every value in reverse order, whether or not Start
+// was called, so that values created before a failure are always released.
+// Only a value whose own Start failed is skipped. Every value is closed even
+// if some fail and all of the errors are returned together.
+func (l *Lifecycle) Close() error {
+	values := l.snapshot()
+	l.lock.Lock()
+	failed, hasFailed := l.failed, l.hasFailed
+	l.lock.Unlock()
+	var errs []error
+	for x := len(values) - 1; x >= 0; x = x - 1 {
+		c, ok := values[x].(io.Closer)
+		if !ok || isNilValue(values[x]) || (hasFailed && x == failed) {
+			continue
+		}
+		if err := c.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close %T due to: %s", values[x], err.Error()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// isNilValue returns true for nil pointers wrapped in an interface.
+func isNilValue(v interface{}) bool {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return rv.IsNil()
+	default:
+		return !rv.IsValid()
+	}
+}
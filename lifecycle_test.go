@@ -0,0 +1,147 @@
+package settings
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type lifecycleItem struct {
+	name     string
+	events   *[]string
+	startErr error
+	closeErr error
+}
+
+func (i *lifecycleItem) Start(context.Context) error {
+	*i.events = append(*i.events, "start "+i.name)
+	return i.startErr
+}
+
+func (i *lifecycleItem) Close() error {
+	*i.events = append(*i.events, "close "+i.name)
+	return i.closeErr
+}
+
+func TestLifecycle(t *testing.T) {
+	var events []string
+	l := &Lifecycle{}
+	l.Add(&lifecycleItem{name: "a", events: &events})
+	l.Add(&testItem{})
+	l.Add((*lifecycleItem)(nil))
+	l.Add(&lifecycleItem{name: "b", events: &events, closeErr: errors.New("b failed")})
+	l.Add(&lifecycleItem{name: "c", events: &events, closeErr: errors.New("c failed")})
+	if err := l.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	err := l.Close()
+	if err == nil || !strings.Contains(err.Error(), "b failed") || !strings.Contains(err.Error(), "c failed") {
+		t.Errorf("Close() error = %v, want both failures", err)
+	}
+	want := []string{"start a", "start b", "start c", "close c", "close b", "close a"}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("events = %v, want %v", events, want)
+	}
+}
+
+func TestLifecycle_startError(t *testing.T) {
+	var events []string
+	l := &Lifecycle{}
+	l.Add(&lifecycleItem{name: "a", events: &events})
+	l.Add(&testItem{})
+	l.Add(&lifecycleItem{name: "b", events: &events, startErr: errors.New("b failed")})
+	l.Add(&lifecycleItem{name: "c", events: &events})
+	if err := l.Start(context.Background()); err == nil {
+		t.Fatal("expected an error")
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"start a", "start b", "close c", "close a", "close c", "close a"}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("events = %v, want %v", events, want)
+	}
+}
+
+func TestLifecycle_closeWithoutStart(t *testing.T) {
+	var events []string
+	l := &Lifecycle{}
+	l.Add(&lifecycleItem{name: "a", events: &events})
+	l.Add(&lifecycleItem{name: "b", events: &events})
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"close b", "close a"}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("events = %v, want %v", events, want)
+	}
+}
+
+type lifecycleDepComponent struct{ events *[]string }
+
+func (*lifecycleDepComponent) Settings() *testConf { return &testConf{} }
+func (c *lifecycleDepComponent) New(context.Context, *testConf) (*lifecycleItem, error) {
+	return &lifecycleItem{name: "dependency", events: c.events}, nil
+}
+
+type lifecycleUser struct {
+	lifecycleItem
+}
+
+type lifecycleUserComponent struct{ events *[]string }
+
+func (*lifecycleUserComponent) Settings() *testConf { return &testConf{} }
+func (c *lifecycleUserComponent) New(_ context.Context, _ *testConf, _ *lifecycleItem) (*lifecycleUser, error) {
+	return &lifecycleUser{lifecycleItem{name: "user", events: c.events}}, nil
+}
+
+func TestContainer_lifecycle(t *testing.T) {
+	var events []string
+	c, err := NewContainer(&lifecycleUserComponent{events: &events}, &lifecycleDepComponent{events: &events})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Build(context.Background(), NewMapSource(map[string]interface{}{})); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"start dependency", "start user", "close user", "close dependency"}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("events = %v, want %v", events, want)
+	}
+}
+
+type lifecycleFailingComponent struct{}
+
+func (*lifecycleFailingComponent) Settings() *testConf { return &testConf{} }
+func (*lifecycleFailingComponent) New(_ context.Context, _ *testConf, _ *lifecycleItem) (*lifecycleUser, error) {
+	return nil, errors.New("failed")
+}
+
+func TestContainer_closeAfterFailedBuild(t *testing.T) {
+	var events []string
+	c, err := NewContainer(&lifecycleFailingComponent{}, &lifecycleDepComponent{events: &events})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Build(context.Background(), NewMapSource(map[string]interface{}{})); err == nil {
+		t.Fatal("Build() accepted a failing component")
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"close dependency"}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("events = %v, want %v", events, want)
+	}
+}
@@ -2,49 +2,151 @@ package settings
 
 import (
 	"context"
+	"errors"
 	"fmt"
 )
 
+// errValueRequired is reported for a required setting without a value.
+var errValueRequired = errors.New("a value is required")
+
 // Load the values for a given batch of settings using
 // the provided source.
 func Load(ctx context.Context, s Source, settings []Setting) error {
 	for _, setting := range settings {
-		v, found := s.Get(ctx, setting.Name())
-		if found {
-			if err := setting.SetValue(v); err != nil {
-				return fmt.Errorf("failed to load setting %s due to: %s", setting.Name(), err.Error())
-			}
+		if _, _, err := loadSetting(ctx, s, setting); err != nil {
+			return fmt.Errorf("failed to load setting %s due to: %s", setting.Name(), err.Error())
 		}
 	}
 	return nil
 }
 
-type groupLoad struct {
-	Path  []string
-	Group Group
+// loadSetting looks up the value of a single setting and loads it if one
+// is found. The raw value from the source is returned along with whether
+// it was found.
+func loadSetting(ctx context.Context, s Source, setting Setting) (interface{}, bool, error) {
+	v, found := s.Get(ctx, setting.Name())
+	if !found {
+		if isRequired(setting) {
+			return nil, false, errValueRequired
+		}
+		return nil, false, nil
+	}
+	return v, true, setting.SetValue(v)
 }
 
-// LoadGroups works similarly to Load except that it will operate recursively
-// on all settings and groups in the given group. Each group name will be
-// added as a path segment leading to an individual setting.
-func LoadGroups(ctx context.Context, s Source, groups []Group) error {
-	stack := make([]groupLoad, 0, len(groups))
+func isRequired(s Setting) bool {
+	r, ok := s.(Required)
+	return ok && r.Required()
+}
+
+// walkGroups visits every group in the given trees, parents before their
+// children, and calls fn with the path of group names leading to, and
+// including, the visited group. Walking stops at the first error.
+func walkGroups(groups []Group, fn func(path []string, g Group) error) error {
+	return walkGroupsAt(nil, groups, fn)
+}
+
+func walkGroupsAt(parent []string, groups []Group, fn func(path []string, g Group) error) error {
 	for _, group := range groups {
-		stack = append(stack, groupLoad{Path: []string{group.Name()}, Group: group})
+		path := make([]string, 0, len(parent)+1)
+		path = append(path, parent...)
+		path = append(path, group.Name())
+		if err := fn(path, group); err != nil {
+			return err
+		}
+		if err := walkGroupsAt(path, group.Groups(), fn); err != nil {
+			return err
+		}
 	}
-	for len(stack) > 0 {
-		var current groupLoad
-		current, stack = stack[len(stack)-1], stack[:len(stack)-1]
-		for _, group := range current.Group.Groups() {
-			newPath := make([]string, 0, len(current.Path)+1)
-			newPath = append(newPath, current.Path...)
-			newPath = append(newPath, group.Name())
-			stack = append(stack, groupLoad{Group: group, Path: newPath})
+	return nil
+}
+
+// Builder is an optional interface for groups that create a value from
+// their settings once they, and all of their sub-trees, are loaded. Groups
+// are only built when creating components with NewComponent or a Container
+// so that loading or inspecting configuration has no side effects.
+type Builder interface {
+	Build(ctx context.Context) error
+}
+
+// buildGroups calls Build on every group that implements Builder. Groups
+// are given in the order they were loaded and are built in reverse so that
+// sub-trees are built before the groups that contain them.
+func buildGroups(ctx context.Context, groups []Group) error {
+	for x := len(groups) - 1; x >= 0; x = x - 1 {
+		b, ok := groups[x].(Builder)
+		if !ok {
+			continue
 		}
-		err := Load(ctx, &PrefixSource{Source: s, Prefix: current.Path}, current.Group.Settings())
-		if err != nil {
-			return fmt.Errorf("failed to load group %s due to: %s", current.Group.Name(), err.Error())
+		if err := b.Build(ctx); err != nil {
+			return fmt.Errorf("failed to build group %s due to: %s", groups[x].Name(), err.Error())
 		}
 	}
 	return nil
 }
+
+// settingLoad is the outcome of loading a single setting.
+type settingLoad struct {
+	// path is the full path of the setting including all group names.
+	path    []string
+	setting Setting
+	raw     interface{}
+	found   bool
+	trace   *lookupTrace
+	err     error
+}
+
+// loadWalk loads every setting in the given groups from the source and
+// calls visit with the outcome of each one, including any error. Walking
+// stops at the first error returned by visit. The groups that were
+// visited are returned in the order they were loaded.
+func loadWalk(ctx context.Context, s Source, groups []Group, visit func(g Group, l settingLoad) error) ([]Group, error) {
+	var loaded []Group
+	err := walkGroups(groups, func(path []string, g Group) error {
+		loaded = append(loaded, g)
+		src := &PrefixSource{Source: s, Prefix: path}
+		for _, setting := range g.Settings() {
+			l := settingLoad{
+				path:    append(append(make([]string, 0, len(path)+1), path...), setting.Name()),
+				setting: setting,
+				trace:   &lookupTrace{},
+			}
+			l.raw, l.found, l.err = loadSetting(context.WithValue(ctx, traceContextKey{}, l.trace), src, setting)
+			if err := visit(g, l); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return loaded, err
+}
+
+// stopOnError is a visit function for loadWalk that stops at the first
+// setting that fails to load.
+func stopOnError(g Group, l settingLoad) error {
+	if l.err != nil {
+		return fmt.Errorf(
+			"failed to load group %s due to: failed to load setting %s due to: %s",
+			g.Name(), l.setting.Name(), l.err.Error(),
+		)
+	}
+	return nil
+}
+
+// LoadGroups works similarly to Load except that it will operate recursively
+// on all settings and groups in the given group. Each group name will be
+// added as a path segment leading to an individual setting.
+func LoadGroups(ctx context.Context, s Source, groups []Group) error {
+	_, err := loadWalk(ctx, s, groups, stopOnError)
+	return err
+}
+
+// loadAndBuildGroups loads the given groups like LoadGroups and then builds
+// every loaded group that implements Builder.
+func loadAndBuildGroups(ctx context.Context, s Source, groups []Group) error {
+	loaded, err := loadWalk(ctx, s, groups, stopOnError)
+	if err != nil {
+		return err
+	}
+	return buildGroups(ctx, loaded)
+}
@@ -0,0 +1,95 @@
+package settings
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// markdownCell escapes text so that it fits within a single table cell.
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, `|`, `\|`)
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+// markdownCode renders text as inline code within a table cell. Empty text
+// renders as an empty cell.
+func markdownCode(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "`" + markdownCell(s) + "`"
+}
+
+// markdownDefault renders the default value of a setting using the same
+// single line form as ExampleEnvSettings. Sensitive defaults are never
+// rendered.
+func markdownDefault(s Setting) string {
+	if isSensitive(s) {
+		return ""
+	}
+	return markdownCode(envValue(s, s.Value()))
+}
+
+// markdownConstraints lists the rules a value must follow beyond its type.
+func markdownConstraints(g Group, s Setting) string {
+	var constraints []string
+	if isRequired(s) {
+		constraints = append(constraints, "required")
+	}
+	if isSensitive(s) {
+		constraints = append(constraints, "sensitive")
+	}
+	if pg, ok := g.(*PluginGroup); ok && s == Setting(pg.TypeValue) {
+		constraints = append(constraints, "one of: "+strings.Join(pg.optionNames(), ", "))
+	}
+	if sp, ok := elementOf(s).(Separated); ok && sp.Separator() != "" {
+		constraints = append(constraints, fmt.Sprintf("separator: `%s`", markdownCell(sp.Separator())))
+	}
+	if tf, ok := elementOf(s).(TimeFormatted); ok && len(tf.Layouts()) > 0 {
+		constraints = append(constraints, fmt.Sprintf("layouts: `%s`", markdownCell(strings.Join(tf.Layouts(), "`, `"))))
+	}
+	return strings.Join(constraints, ", ")
+}
+
+// MarkdownGroups renders reference documentation for a set of groups as
+// Markdown. Each group is a heading, nested beneath the heading of its
+// parent, followed by its description and a table of its settings. Every
+// row lists the full dotted path of the setting, the name of its ENV var,
+// its type, default, description, and constraints. Paths and ENV var names
+// follow the same rules as ExampleYamlGroups and ExampleEnvGroups.
+func MarkdownGroups(groups []Group) string {
+	var b bytes.Buffer
+	_ = walkGroups(groups, func(path []string, g Group) error {
+		level := len(path) + 1
+		if level > 6 {
+			level = 6
+		}
+		if b.Len() > 0 {
+			_, _ = b.WriteString("\n")
+		}
+		_, _ = b.WriteString(fmt.Sprintf("%s %s\n", strings.Repeat("#", level), strings.ToLower(strings.Join(path, "."))))
+		if g.Description() != "" {
+			_, _ = b.WriteString(fmt.Sprintf("\n%s\n", g.Description()))
+		}
+		if len(g.Settings()) < 1 {
+			return nil
+		}
+		_, _ = b.WriteString("\n| Path | ENV | Type | Default | Description | Constraints |\n")
+		_, _ = b.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+		for _, s := range g.Settings() {
+			settingPath := append(append(make([]string, 0, len(path)+1), path...), s.Name())
+			_, _ = b.WriteString(fmt.Sprintf(
+				"| %s | %s | %s | %s | %s | %s |\n",
+				markdownCode(strings.ToLower(strings.Join(settingPath, "."))),
+				markdownCode(envName(settingPath...)),
+				markdownCode(typeHint(s.Value())),
+				markdownDefault(s),
+				markdownCell(s.Description()),
+				markdownConstraints(g, s),
+			))
+		}
+		return nil
+	})
+	return b.String()
+}
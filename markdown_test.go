@@ -0,0 +1,47 @@
+package settings
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andreyvit/diff"
+)
+
+type markdownDB struct {
+	Password Secret   `description:"The database password."`
+	Hosts    []string `separator:"," description:"Hosts | replicas to use."`
+}
+
+type markdownConf struct {
+	Name  string    `required:"true" description:"The service name."`
+	Since time.Time `layout:"DateOnly"`
+	DB    *markdownDB
+}
+
+func TestMarkdownGroups(t *testing.T) {
+	g, err := Convert(&markdownConf{
+		Name:  "svc",
+		Since: time.Date(2020, time.March, 1, 0, 0, 0, 0, time.UTC),
+		DB:    &markdownDB{Password: "hunter2", Hosts: []string{"a", "b"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "## markdownconf\n" +
+		"\n" +
+		"| Path | ENV | Type | Default | Description | Constraints |\n" +
+		"| --- | --- | --- | --- | --- | --- |\n" +
+		"| `markdownconf.since` | `MARKDOWNCONF_SINCE` | `time.Time` | `2020-03-01` |  | layouts: `2006-01-02` |\n" +
+		"| `markdownconf.name` | `MARKDOWNCONF_NAME` | `string` | `svc` | The service name. | required |\n" +
+		"\n" +
+		"### markdownconf.markdowndb\n" +
+		"\n" +
+		"| Path | ENV | Type | Default | Description | Constraints |\n" +
+		"| --- | --- | --- | --- | --- | --- |\n" +
+		"| `markdownconf.markdowndb.hosts` | `MARKDOWNCONF_MARKDOWNDB_HOSTS` | `[]string` | `a,b` | Hosts \\| replicas to use. | separator: `,` |\n" +
+		"| `markdownconf.markdowndb.password` | `MARKDOWNCONF_MARKDOWNDB_PASSWORD` | `settings.Secret` |  | The database password. | sensitive |\n"
+	got := MarkdownGroups([]Group{g})
+	if got != want {
+		t.Errorf("MarkdownGroups() = %v, want %v\n%s", got, want, diff.LineDiff(got, want))
+	}
+}
@@ -0,0 +1,265 @@
+package settings
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// optional is implemented by every Optional so that Convert can manage the
+// wrapped value without knowing its type.
+type optional interface {
+	optionalValue() reflect.Value
+	markSet()
+	IsSet() bool
+}
+
+var optionalType = reflect.TypeOf((*optional)(nil)).Elem()
+
+// isOptionalType returns true if the type is an instance of Optional.
+func isOptionalType(t reflect.Type) bool {
+	return reflect.PointerTo(t).Implements(optionalType)
+}
+
+// Optional wraps a value of any type that the library can convert and
+// records whether a source supplied it. This makes it possible to tell an
+// explicit value apart from the default.
+type Optional[T any] struct {
+	value T
+	set   bool
+}
+
+// NewOptional creates an Optional that holds a default value but is not
+// marked as set.
+func NewOptional[T any](fallback T) Optional[T] {
+	return Optional[T]{value: fallback}
+}
+
+// Get returns the value and whether it was set.
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.set
+}
+
+// Value returns the value whether or not it was set.
+func (o Optional[T]) Value() T {
+	return o.value
+}
+
+// IsSet returns true if a source supplied the value.
+func (o Optional[T]) IsSet() bool {
+	return o.set
+}
+
+// Or returns the value if it was set and the fallback otherwise.
+func (o Optional[T]) Or(fallback T) T {
+	if !o.set {
+		return fallback
+	}
+	return o.value
+}
+
+// Set replaces the value and marks it as set.
+func (o *Optional[T]) Set(v T) {
+	o.value = v
+	o.set = true
+}
+
+func (o *Optional[T]) optionalValue() reflect.Value {
+	return reflect.ValueOf(&o.value).Elem()
+}
+
+func (o *Optional[T]) markSet() {
+	o.set = true
+}
+
+// OptionalSetting manages an instance of Optional. The wrapped value is
+// converted by ElementValue, which is a setting of the wrapped type with
+// any separator, layout, or location of the field. Values are loaded into
+// ElementValue and then copied into the managed Optional. OptionalValue
+// must be a pointer to the managed Optional.
+type OptionalSetting struct {
+	*BaseSetting
+	OptionalValue interface{}
+	ElementValue  Setting
+}
+
+// NewOptionalSetting creates an OptionalSetting that manages the Optional
+// referenced by the given pointer.
+func NewOptionalSetting(name string, description string, value interface{}) *OptionalSetting {
+	s := &OptionalSetting{
+		BaseSetting: &BaseSetting{
+			NameValue:        name,
+			DescriptionValue: description,
+		},
+		OptionalValue: value,
+	}
+	s.ElementValue, _ = elementSetting(s.BaseSetting, value.(optional).optionalValue().Type())
+	return s
+}
+
+func (s *OptionalSetting) detach() Setting {
+	c := *s
+	c.OptionalValue = copyPointer(s.OptionalValue)
+	if s.ElementValue != nil {
+		c.ElementValue = detachSetting(s.ElementValue)
+	}
+	return &c
+}
+
+// Value returns the wrapped value whether or not it was set.
+func (s *OptionalSetting) Value() interface{} {
+	return s.OptionalValue.(optional).optionalValue().Interface()
+}
+
+// SetValue converts the given value, replaces the wrapped value, and marks
+// the Optional as set. A value that isAbsent leaves the Optional unchanged.
+func (s *OptionalSetting) SetValue(v interface{}) error {
+	o := s.OptionalValue.(optional)
+	if isAbsent(v, o.optionalValue().Type()) {
+		return nil
+	}
+	if err := loadElement(s.ElementValue, o.optionalValue(), v); err != nil {
+		return err
+	}
+	o.markSet()
+	return nil
+}
+
+// PointerSetting manages a pointer to any type that the library can
+// convert. The pointer is only assigned when a value is set so a nil
+// pointer indicates that no source supplied a value. Values are converted
+// by ElementValue in the same way as for an OptionalSetting. PointerValue
+// must be a pointer to the managed pointer.
+type PointerSetting struct {
+	*BaseSetting
+	PointerValue interface{}
+	ElementValue Setting
+}
+
+// NewPointerSetting creates a PointerSetting that manages the pointer
+// referenced by the given value.
+func NewPointerSetting(name string, description string, value interface{}) *PointerSetting {
+	s := &PointerSetting{
+		BaseSetting: &BaseSetting{
+			NameValue:        name,
+			DescriptionValue: description,
+		},
+		PointerValue: value,
+	}
+	s.ElementValue, _ = elementSetting(s.BaseSetting, reflect.TypeOf(value).Elem().Elem())
+	return s
+}
+
+func (s *PointerSetting) detach() Setting {
+	c := *s
+	c.PointerValue = copyPointer(s.PointerValue)
+	if s.ElementValue != nil {
+		c.ElementValue = detachSetting(s.ElementValue)
+	}
+	return &c
+}
+
+// Value returns the managed pointer, which is nil if it was never set.
+func (s *PointerSetting) Value() interface{} {
+	return reflect.ValueOf(s.PointerValue).Elem().Interface()
+}
+
+// SetValue converts the given value into a newly allocated instance and
+// points the managed pointer to it. A value that isAbsent leaves the
+// pointer unchanged.
+func (s *PointerSetting) SetValue(v interface{}) error {
+	rv := reflect.ValueOf(s.PointerValue).Elem()
+	if isAbsent(v, rv.Type().Elem()) {
+		return nil
+	}
+	elem := reflect.New(rv.Type().Elem())
+	if err := loadElement(s.ElementValue, elem.Elem(), v); err != nil {
+		return err
+	}
+	rv.Set(elem)
+	return nil
+}
+
+// isUnset returns true for a pointer setting with a nil pointer and for an
+// Optional setting that was never set. Such settings have no value that
+// could be rendered and loaded back, so renderers leave them out.
+func isUnset(s Setting) bool {
+	switch ss := s.(type) {
+	case *PointerSetting:
+		return reflect.ValueOf(ss.PointerValue).Elem().IsNil()
+	case *OptionalSetting:
+		return !ss.OptionalValue.(optional).IsSet()
+	default:
+		return false
+	}
+}
+
+// isAbsent returns true if a raw value given for a pointer or Optional with
+// elements of type t means that no value was given. This is the case for a
+// null value, such as `p:` in YAML or `"p": null` in JSON, and for an empty
+// string, such as `P=` in the environment, unless the element is a string.
+func isAbsent(v interface{}, t reflect.Type) bool {
+	if v == nil {
+		return true
+	}
+	switch vv := v.(type) {
+	case string:
+		return vv == "" && t.Kind() != reflect.String
+	case []byte:
+		return len(vv) == 0 && t.Kind() != reflect.String
+	default:
+		return false
+	}
+}
+
+// elementSetting creates the setting that converts values for the element
+// of a pointer or Optional. The base is marked as sensitive if the element
+// is, such as for a *Secret.
+func elementSetting(base *BaseSetting, t reflect.Type) (Setting, error) {
+	elem, err := settingFromValue(&BaseSetting{NameValue: base.NameValue}, reflect.New(t).Elem())
+	if err != nil {
+		return nil, err
+	}
+	if isSensitive(elem) {
+		base.SensitiveValue = true
+	}
+	return elem, nil
+}
+
+// elementOf returns the setting that converts values for a pointer or
+// Optional, or the setting itself for any other type.
+func elementOf(s Setting) Setting {
+	var elem Setting
+	switch es := s.(type) {
+	case *PointerSetting:
+		elem = es.ElementValue
+	case *OptionalSetting:
+		elem = es.ElementValue
+	default:
+	}
+	if elem == nil {
+		return s
+	}
+	return elem
+}
+
+// loadElement converts a value with the element setting and stores the
+// result in dst. The element setting may manage the underlying type of
+// dst, such as int for `type Port int`.
+func loadElement(elem Setting, dst reflect.Value, v interface{}) error {
+	if elem == nil {
+		return fmt.Errorf("unknown setting type %s", dst.Type())
+	}
+	if err := elem.SetValue(v); err != nil {
+		return err
+	}
+	ev := reflect.ValueOf(elem.Value())
+	switch {
+	case !ev.IsValid():
+		ev = reflect.Zero(dst.Type())
+	case !ev.Type().AssignableTo(dst.Type()):
+		ev = ev.Convert(dst.Type())
+	default:
+	}
+	dst.Set(ev)
+	return nil
+}
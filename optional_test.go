@@ -0,0 +1,193 @@
+package settings
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/andreyvit/diff"
+)
+
+type optionalConf struct {
+	Pool    *int
+	Name    *string
+	Token   *Secret
+	Unset   *time.Duration
+	Level   Optional[int]
+	Timeout Optional[time.Duration]
+}
+
+func TestOptional_load(t *testing.T) {
+	conf := &optionalConf{Timeout: NewOptional(time.Second)}
+	g, err := Convert(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewMapSource(map[string]interface{}{
+		"optionalconf": map[string]interface{}{
+			"pool":  "10",
+			"name":  "db",
+			"token": "hunter2",
+			"level": 3,
+		},
+	})
+	if err := LoadGroups(context.Background(), s, []Group{g}); err != nil {
+		t.Fatal(err)
+	}
+	if conf.Pool == nil || *conf.Pool != 10 {
+		t.Errorf("Pool = %v, want 10", conf.Pool)
+	}
+	if conf.Name == nil || *conf.Name != "db" {
+		t.Errorf("Name = %v, want db", conf.Name)
+	}
+	if conf.Token == nil || conf.Token.Reveal() != "hunter2" {
+		t.Errorf("Token was not set")
+	}
+	if conf.Unset != nil {
+		t.Errorf("Unset = %v, want nil", *conf.Unset)
+	}
+	if v, ok := conf.Level.Get(); !ok || v != 3 {
+		t.Errorf("Level.Get() = %d, %t, want 3, true", v, ok)
+	}
+	if conf.Timeout.IsSet() {
+		t.Error("Timeout was marked as set")
+	}
+	if conf.Timeout.Value() != time.Second {
+		t.Errorf("Timeout.Value() = %s, want 1s", conf.Timeout.Value())
+	}
+	if conf.Timeout.Or(time.Minute) != time.Minute {
+		t.Errorf("Timeout.Or() = %s, want 1m0s", conf.Timeout.Or(time.Minute))
+	}
+	for _, set := range g.Settings() {
+		if set.Name() == "Token" && !isSensitive(set) {
+			t.Error("pointer to Secret is not sensitive")
+		}
+	}
+}
+
+func TestOptional_badValue(t *testing.T) {
+	conf := &optionalConf{}
+	g, err := Convert(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewMapSource(map[string]interface{}{
+		"optionalconf": map[string]interface{}{
+			"pool":  "ten",
+			"level": "three",
+		},
+	})
+	if err := LoadGroups(context.Background(), s, []Group{g}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if conf.Pool != nil {
+		t.Errorf("Pool = %d, want nil", *conf.Pool)
+	}
+	if conf.Level.IsSet() {
+		t.Error("Level was marked as set")
+	}
+}
+
+func TestOptional_absent(t *testing.T) {
+	yml, err := NewYAMLSource([]byte("optionalconf:\n  pool:\n  name:\n  unset: null\n  level:\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := NewEnvSource([]string{"OPTIONALCONF_POOL=", "OPTIONALCONF_NAME=", "OPTIONALCONF_LEVEL="})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		source   Source
+		wantName *string
+	}{
+		{name: "null", source: yml},
+		{name: "empty string", source: env, wantName: new(string)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := &optionalConf{}
+			g, err := Convert(conf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := LoadGroups(context.Background(), tt.source, []Group{g}); err != nil {
+				t.Fatal(err)
+			}
+			if conf.Pool != nil || conf.Unset != nil || conf.Level.IsSet() {
+				t.Errorf("LoadGroups() set absent values: %#v", conf)
+			}
+			if !reflect.DeepEqual(conf.Name, tt.wantName) {
+				t.Errorf("Name = %#v, want %#v", conf.Name, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestOptional_Set(t *testing.T) {
+	var o Optional[string]
+	if _, ok := o.Get(); ok {
+		t.Error("zero Optional was marked as set")
+	}
+	o.Set("value")
+	if v, ok := o.Get(); !ok || v != "value" {
+		t.Errorf("Get() = %s, %t, want value, true", v, ok)
+	}
+}
+
+type optionalPort int
+
+type optionalFormatConf struct {
+	Hosts *[]string           `separator:","`
+	Since Optional[time.Time] `layout:"DateOnly"`
+	Until *time.Time          `layout:"DateOnly" location:"America/New_York"`
+	Ports Optional[[]int]     `separator:";"`
+	Port  *optionalPort
+}
+
+func TestOptional_elementOptions(t *testing.T) {
+	conf := &optionalFormatConf{}
+	g, err := Convert(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewMapSource(map[string]interface{}{
+		"optionalformatconf": map[string]interface{}{
+			"hosts": "a b,c",
+			"since": "2020-03-01",
+			"until": "2021-04-02",
+			"ports": "80;443",
+			"port":  "8080",
+		},
+	})
+	if err := LoadGroups(context.Background(), s, []Group{g}); err != nil {
+		t.Fatal(err)
+	}
+	if conf.Hosts == nil || !reflect.DeepEqual(*conf.Hosts, []string{"a b", "c"}) {
+		t.Errorf("Hosts = %v, want [a b c]", conf.Hosts)
+	}
+	if v, ok := conf.Since.Get(); !ok || !v.Equal(time.Date(2020, time.March, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Since = %v, %t", v, ok)
+	}
+	ny, _ := time.LoadLocation("America/New_York")
+	if conf.Until == nil || !conf.Until.Equal(time.Date(2021, time.April, 2, 0, 0, 0, 0, ny)) {
+		t.Errorf("Until = %v", conf.Until)
+	}
+	if v, ok := conf.Ports.Get(); !ok || !reflect.DeepEqual(v, []int{80, 443}) {
+		t.Errorf("Ports = %v, %t", v, ok)
+	}
+	if conf.Port == nil || *conf.Port != 8080 {
+		t.Errorf("Port = %v, want 8080", conf.Port)
+	}
+	want := `OPTIONALFORMATCONF_PORT="8080"
+OPTIONALFORMATCONF_PORTS="80;443"
+OPTIONALFORMATCONF_UNTIL="2021-04-02"
+OPTIONALFORMATCONF_SINCE="2020-03-01"
+OPTIONALFORMATCONF_HOSTS="a b,c"
+`
+	if got := DumpEnvGroups([]Group{g}); got != want {
+		t.Errorf("DumpEnvGroups() = %v, want %v\n%s", got, want, diff.LineDiff(got, want))
+	}
+}
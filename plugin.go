@@ -0,0 +1,208 @@
+package settings
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cast"
+)
+
+// pluginTypeName is the name of the setting that selects an implementation
+// for a plugin field.
+const pluginTypeName = "type"
+
+var plugins = struct {
+	sync.RWMutex
+	m map[reflect.Type]map[string]interface{}
+}{m: make(map[reflect.Type]map[string]interface{})}
+
+// RegisterComponent installs an implementation of the Component contract as
+// an option for fields of the given interface type. When a struct given to
+// Convert contains a field of that type, the "type" key beneath the field
+// selects the registered name of the component that is used to create the
+// value. Registering a name more than once replaces the previous component.
+func RegisterComponent(iface reflect.Type, name string, component interface{}) error {
+	if iface == nil || iface.Kind() != reflect.Interface {
+		return fmt.Errorf("components can only be registered for interface types")
+	}
+	if name == "" {
+		return fmt.Errorf("component for %s must have a name", iface)
+	}
+	if err := VerifyComponent(component); err != nil {
+		return err
+	}
+	out := reflect.ValueOf(component).MethodByName("New").Type().Out(0)
+	if !out.Implements(iface) {
+		return fmt.Errorf("component %s creates %s which does not implement %s", name, out, iface)
+	}
+	plugins.Lock()
+	defer plugins.Unlock()
+	if plugins.m[iface] == nil {
+		plugins.m[iface] = make(map[string]interface{})
+	}
+	plugins.m[iface][name] = component
+	return nil
+}
+
+// unregisterComponent removes the component with the given name for an
+// interface type so that tests can undo a registration.
+func unregisterComponent(iface reflect.Type, name string) {
+	plugins.Lock()
+	defer plugins.Unlock()
+	delete(plugins.m[iface], name)
+	if len(plugins.m[iface]) == 0 {
+		delete(plugins.m, iface)
+	}
+}
+
+// lookupComponents returns a copy of the components registered for a type.
+func lookupComponents(t reflect.Type) map[string]interface{} {
+	plugins.RLock()
+	defer plugins.RUnlock()
+	result := make(map[string]interface{}, len(plugins.m[t]))
+	for name, component := range plugins.m[t] {
+		result[name] = component
+	}
+	return result
+}
+
+func hasComponents(t reflect.Type) bool {
+	plugins.RLock()
+	defer plugins.RUnlock()
+	return len(plugins.m[t]) > 0
+}
+
+// PluginGroup manages a field of an interface type that is populated by one
+// of the components registered for that type. The group contains a "type"
+// setting and a sub-tree of settings for each registered component. Once a
+// type is selected, only the sub-tree of the selected component is included
+// in the group so that the settings of the other components are ignored.
+type PluginGroup struct {
+	NameValue        string
+	DescriptionValue string
+	TypeValue        *TypedSetting[string]
+	// Options contains the settings of each component by registered name.
+	Options    map[string]Group
+	components map[string]interface{}
+	configs    map[string]reflect.Value
+	target     reflect.Value
+}
+
+// newPluginGroup creates a PluginGroup that populates the given field. The
+// settings of every registered component are converted up front so that
+// they can be rendered as examples and loaded like any other group.
+func newPluginGroup(name string, description string, target reflect.Value) (*PluginGroup, error) {
+	components := lookupComponents(target.Type())
+	g := &PluginGroup{
+		NameValue:        name,
+		DescriptionValue: description,
+		Options:          make(map[string]Group, len(components)),
+		components:       components,
+		configs:          make(map[string]reflect.Value, len(components)),
+		target:           target,
+	}
+	for option, component := range components {
+		config := reflect.ValueOf(component).MethodByName("Settings").Call(nil)[0]
+		sub, err := Convert(config.Interface())
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert component %s due to: %s", option, err.Error())
+		}
+		g.Options[option] = &SettingGroup{
+			NameValue:        option,
+			DescriptionValue: sub.Description(),
+			GroupValues:      sub.Groups(),
+			SettingValues:    sub.Settings(),
+		}
+		g.configs[option] = config
+	}
+	names := g.optionNames()
+	g.TypeValue = NewTypedSetting(
+		pluginTypeName,
+		fmt.Sprintf("The implementation to use. One of: %s", strings.Join(names, ", ")),
+		"",
+		func(v interface{}) (string, error) {
+			option, err := cast.ToStringE(v)
+			if err != nil {
+				return "", err
+			}
+			if _, ok := components[option]; !ok && option != "" {
+				return "", fmt.Errorf("unknown type %s. expected one of: %s", option, strings.Join(names, ", "))
+			}
+			return option, nil
+		},
+	)
+	return g, nil
+}
+
+func (g *PluginGroup) optionNames() []string {
+	names := make([]string, 0, len(g.Options))
+	for name := range g.Options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Name returns the group name as it appears in the configuration.
+func (g *PluginGroup) Name() string {
+	return g.NameValue
+}
+
+// Description returns the group description.
+func (g *PluginGroup) Description() string {
+	return g.DescriptionValue
+}
+
+// Groups returns the settings of the selected component or, if none is
+// selected, the settings of every component ordered by name.
+func (g *PluginGroup) Groups() []Group {
+	if option, ok := g.Options[g.Selected()]; ok {
+		return []Group{option}
+	}
+	result := make([]Group, 0, len(g.Options))
+	for _, name := range g.optionNames() {
+		result = append(result, g.Options[name])
+	}
+	return result
+}
+
+// Settings returns the setting used to select a component.
+func (g *PluginGroup) Settings() []Setting {
+	return []Setting{g.TypeValue}
+}
+
+// Selected returns the name of the selected component, if any.
+func (g *PluginGroup) Selected() string {
+	return g.TypeValue.Value().(string)
+}
+
+// detach copies the group so that a type, and the settings of the selected
+// component, can be loaded without changing the original.
+func (g *PluginGroup) detach() Group {
+	c := *g
+	c.TypeValue = g.TypeValue.detach().(*TypedSetting[string])
+	c.Options = make(map[string]Group, len(g.Options))
+	for name, option := range g.Options {
+		c.Options[name] = detachGroup(option)
+	}
+	return &c
+}
+
+// Build creates a value with the selected component and assigns it to the
+// managed field. The field is left unchanged if no component is selected.
+func (g *PluginGroup) Build(ctx context.Context) error {
+	option := g.Selected()
+	if option == "" {
+		return nil
+	}
+	v, err := callNew(ctx, g.components[option], g.configs[option])
+	if err != nil {
+		return err
+	}
+	g.target.Set(v)
+	return nil
+}
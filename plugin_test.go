@@ -0,0 +1,229 @@
+package settings
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/andreyvit/diff"
+)
+
+type testCache interface {
+	Kind() string
+}
+
+type memoryCacheConf struct {
+	Size int `description:"number of entries"`
+}
+
+func (*memoryCacheConf) Name() string { return "ignored" }
+
+type memoryCache struct{ size int }
+
+func (*memoryCache) Kind() string { return "memory" }
+
+type memoryCacheComponent struct{}
+
+func (*memoryCacheComponent) Settings() *memoryCacheConf { return &memoryCacheConf{Size: 10} }
+func (*memoryCacheComponent) New(_ context.Context, c *memoryCacheConf) (*memoryCache, error) {
+	return &memoryCache{size: c.Size}, nil
+}
+
+type redisCacheConf struct {
+	Host string `description:"redis host"`
+}
+
+type redisCache struct{ host string }
+
+func (*redisCache) Kind() string { return "redis" }
+
+type redisCacheComponent struct{}
+
+func (*redisCacheComponent) Settings() *redisCacheConf { return &redisCacheConf{Host: "localhost"} }
+func (*redisCacheComponent) New(_ context.Context, c *redisCacheConf) (*redisCache, error) {
+	return &redisCache{host: c.Host}, nil
+}
+
+// registerTestComponent installs a component for the duration of a test.
+func registerTestComponent(t *testing.T, iface reflect.Type, name string, component interface{}) {
+	t.Helper()
+	if err := RegisterComponent(iface, name, component); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { unregisterComponent(iface, name) })
+}
+
+// registerTestCaches installs the memory and redis testCache components
+// for the duration of a test.
+func registerTestCaches(t *testing.T) {
+	t.Helper()
+	cacheType := reflect.TypeOf((*testCache)(nil)).Elem()
+	registerTestComponent(t, cacheType, "memory", &memoryCacheComponent{})
+	registerTestComponent(t, cacheType, "redis", &redisCacheComponent{})
+}
+
+type pluginConf struct {
+	Cache testCache `description:"the cache"`
+}
+
+func TestPluginGroup_load(t *testing.T) {
+	registerTestCaches(t)
+	tests := []struct {
+		name    string
+		source  map[string]interface{}
+		want    testCache
+		wantErr bool
+	}{
+		{
+			name:   "memory",
+			source: map[string]interface{}{"cache": map[string]interface{}{"type": "memory", "memory": map[string]interface{}{"size": 5}}},
+			want:   &memoryCache{size: 5},
+		},
+		{
+			name:   "redis default",
+			source: map[string]interface{}{"cache": map[string]interface{}{"type": "redis"}},
+			want:   &redisCache{host: "localhost"},
+		},
+		{
+			name: "unselected options are ignored",
+			source: map[string]interface{}{"cache": map[string]interface{}{
+				"type":   "redis",
+				"memory": map[string]interface{}{"size": "many"},
+			}},
+			want: &redisCache{host: "localhost"},
+		},
+		{
+			name:   "no type",
+			source: map[string]interface{}{},
+			want:   nil,
+		},
+		{
+			name:    "unknown type",
+			source:  map[string]interface{}{"cache": map[string]interface{}{"type": "disk"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := &pluginConf{}
+			g, err := Convert(conf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = loadAndBuildGroups(context.Background(), NewMapSource(map[string]interface{}{"pluginconf": tt.source}), []Group{g})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadAndBuildGroups() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(conf.Cache, tt.want) {
+				t.Errorf("Cache = %#v, want %#v", conf.Cache, tt.want)
+			}
+		})
+	}
+}
+
+func TestPluginGroup_readOnly(t *testing.T) {
+	registerTestCaches(t)
+	s := NewMapSource(map[string]interface{}{"pluginconf": map[string]interface{}{
+		"cache": map[string]interface{}{"type": "redis"},
+	}})
+	conf := &pluginConf{}
+	g, err := Convert(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = LoadGroups(context.Background(), s, []Group{g}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ExplainGroups(context.Background(), s, []Group{g}); err != nil {
+		t.Fatal(err)
+	}
+	if conf.Cache != nil {
+		t.Errorf("Cache = %#v, want no component created while loading", conf.Cache)
+	}
+}
+
+func TestPluginGroup_example(t *testing.T) {
+	registerTestCaches(t)
+	g, err := Convert(&pluginConf{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `pluginConf:
+  Cache:
+    # (string) The implementation to use. One of: memory, redis
+    type: ""
+    memory:
+      # (int) number of entries
+      size: 10
+    redis:
+      # (string) redis host
+      host: "localhost"
+`
+	if got := ExampleYamlGroups([]Group{g}); got != want {
+		t.Errorf("ExampleYamlGroups() = %v, want %v\n%s", got, want, diff.LineDiff(got, want))
+	}
+}
+
+func TestPluginGroup_component(t *testing.T) {
+	registerTestCaches(t)
+	src := NewMapSource(map[string]interface{}{
+		"pluginconf": map[string]interface{}{"cache": map[string]interface{}{"type": "memory"}},
+	})
+	r := new(pluginResult)
+	if err := NewComponent(context.Background(), src, &pluginComponent{}, r); err != nil {
+		t.Fatal(err)
+	}
+	if r.cache.Kind() != "memory" {
+		t.Errorf("Kind() = %s, want memory", r.cache.Kind())
+	}
+}
+
+type pluginResult struct{ cache testCache }
+
+type pluginComponent struct{}
+
+func (*pluginComponent) Settings() *pluginConf { return &pluginConf{} }
+func (*pluginComponent) New(_ context.Context, c *pluginConf) (*pluginResult, error) {
+	return &pluginResult{cache: c.Cache}, nil
+}
+
+func TestRegisterComponent(t *testing.T) {
+	cacheType := reflect.TypeOf((*testCache)(nil)).Elem()
+	tests := []struct {
+		name      string
+		iface     reflect.Type
+		option    string
+		component interface{}
+		wantErr   string
+	}{
+		{name: "not an interface", iface: reflect.TypeOf(""), option: "x", component: &memoryCacheComponent{}, wantErr: "interface"},
+		{name: "no name", iface: cacheType, option: "", component: &memoryCacheComponent{}, wantErr: "name"},
+		{name: "not a component", iface: cacheType, option: "x", component: &missingNew{}, wantErr: "New"},
+		{name: "wrong output", iface: cacheType, option: "x", component: &testComponent{}, wantErr: "does not implement"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RegisterComponent(tt.iface, tt.option, tt.component)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("RegisterComponent() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUnregisterComponent(t *testing.T) {
+	cacheType := reflect.TypeOf((*testCache)(nil)).Elem()
+	t.Run("registered", func(t *testing.T) {
+		registerTestCaches(t)
+		if got := len(lookupComponents(cacheType)); got != 2 {
+			t.Errorf("lookupComponents() found %d components during the test, want 2", got)
+		}
+	})
+	if got := len(lookupComponents(cacheType)); got != 0 {
+		t.Errorf("lookupComponents() found %d components after the test finished, want 0", got)
+	}
+	if _, err := Convert(&pluginConf{}); err == nil {
+		t.Error("Convert() accepted a plugin field with no registered components")
+	}
+}
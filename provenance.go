@@ -0,0 +1,140 @@
+package settings
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+type traceContextKey struct{}
+
+// lookupTrace collects details about a single lookup as it passes through
+// the Source implementations of this package. All methods are safe to call
+// on a nil trace so that sources do not need to check if tracing is active.
+type lookupTrace struct {
+	sources    []string
+	expansions []string
+}
+
+func traceFromContext(ctx context.Context) *lookupTrace {
+	if ctx == nil {
+		return nil
+	}
+	t, _ := ctx.Value(traceContextKey{}).(*lookupTrace)
+	return t
+}
+
+// source records the name of a source that supplied a value. Wrapping
+// sources record their names after the sources they wrap so each name is
+// prepended to keep the outermost name first.
+func (t *lookupTrace) source(name string) {
+	if t == nil {
+		return
+	}
+	t.sources = append([]string{name}, t.sources...)
+}
+
+// expand records a ${} reference that is being followed. Sources nested in
+// a MultiSource attempt the same expansion as the MultiSource itself so a
+// repeat of the last reference is not recorded again.
+func (t *lookupTrace) expand(reference string) {
+	if t == nil {
+		return
+	}
+	if len(t.expansions) > 0 && t.expansions[len(t.expansions)-1] == reference {
+		return
+	}
+	t.expansions = append(t.expansions, reference)
+}
+
+func (t *lookupTrace) resetSources() []string {
+	if t == nil {
+		return nil
+	}
+	names := t.sources
+	t.sources = nil
+	return names
+}
+
+func (t *lookupTrace) restoreSources(names []string) {
+	if t == nil {
+		return
+	}
+	t.sources = names
+}
+
+// Provenance describes how the value of a single setting was resolved.
+type Provenance struct {
+	// Path is the full path of the setting including all group names.
+	Path []string
+	// Value is the value of the setting after loading.
+	Value interface{}
+	// Sensitive is true if the setting contains a secret. The Value and Raw
+	// of a sensitive setting are redacted in the same way as a dump.
+	Sensitive bool
+	// Found is false when no source had a value and the default was kept.
+	Found bool
+	// Raw is the value returned by the source before conversion.
+	Raw interface{}
+	// Source is the name of the NamedSource that supplied the value. Names
+	// of nested NamedSources are joined with a "/", outermost first. The
+	// Source is empty if the value was not found or was supplied by a
+	// source without a name.
+	Source string
+	// Expansions lists every ${} reference that was followed, in order.
+	Expansions []string
+}
+
+// String renders the provenance as a single line of text.
+func (p Provenance) String() string {
+	path := strings.Join(p.Path, ".")
+	if !p.Found {
+		return fmt.Sprintf("%s = %v (default)", path, p.Value)
+	}
+	source := p.Source
+	if source == "" {
+		source = "unnamed source"
+	}
+	var b strings.Builder
+	_, _ = b.WriteString(fmt.Sprintf("%s = %v (from %s, raw %#v", path, p.Value, source, p.Raw))
+	if len(p.Expansions) > 0 {
+		_, _ = b.WriteString(fmt.Sprintf(", via %s", strings.Join(p.Expansions, " -> ")))
+	}
+	_, _ = b.WriteString(")")
+	return b.String()
+}
+
+// newProvenance describes the outcome of loading a single setting.
+func newProvenance(l settingLoad) Provenance {
+	p := Provenance{
+		Path:       l.path,
+		Value:      l.setting.Value(),
+		Found:      l.found,
+		Source:     strings.Join(l.trace.sources, "/"),
+		Expansions: l.trace.expansions,
+	}
+	if l.found {
+		p.Raw = l.raw
+	}
+	if isSensitive(l.setting) {
+		p.Sensitive = true
+		p.Value, _ = redactedValue(l.setting)
+		if l.found {
+			p.Raw = redactedDisplay
+		}
+	}
+	return p
+}
+
+// ExplainGroups loads the given groups exactly like LoadGroups and returns
+// the provenance of every setting in the order they were loaded. If loading
+// fails then the provenance gathered up to, and including, the failed
+// setting is returned along with the error.
+func ExplainGroups(ctx context.Context, s Source, groups []Group) ([]Provenance, error) {
+	var result []Provenance
+	_, err := loadWalk(ctx, s, groups, func(g Group, l settingLoad) error {
+		result = append(result, newProvenance(l))
+		return stopOnError(g, l)
+	})
+	return result, err
+}
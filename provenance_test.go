@@ -0,0 +1,146 @@
+package settings
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type explainConf struct {
+	Timeout time.Duration
+	Name    string
+	Retries int
+}
+
+func TestExplainGroups(t *testing.T) {
+	env, _ := NewEnvSource([]string{"EXPLAINCONF_TIMEOUT=5s", "SHARED_NAME=from-env"})
+	yml, _ := NewYAMLSource([]byte(`
+explainconf:
+  timeout: "1s"
+  name: "${SHARED_NAME}"
+`))
+	s := &NamedSource{
+		NameValue: "all",
+		Source: MultiSource{
+			&NamedSource{NameValue: "env", Source: env},
+			&NamedSource{NameValue: "yaml", Source: yml},
+		},
+	}
+	conf := &explainConf{Retries: 3}
+	g, err := Convert(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ExplainGroups(context.Background(), s, []Group{g})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Convert gathers struct fields in reverse order.
+	want := []Provenance{
+		{
+			Path:  []string{"explainConf", "Retries"},
+			Value: 3,
+		},
+		{
+			Path:       []string{"explainConf", "Name"},
+			Value:      "from-env",
+			Found:      true,
+			Raw:        "from-env",
+			Source:     "all/env",
+			Expansions: []string{"${SHARED_NAME}"},
+		},
+		{
+			Path:   []string{"explainConf", "Timeout"},
+			Value:  5 * time.Second,
+			Found:  true,
+			Raw:    "5s",
+			Source: "all/env",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExplainGroups() = %v, want %v", got, want)
+	}
+	if conf.Timeout != 5*time.Second || conf.Name != "from-env" {
+		t.Errorf("ExplainGroups() did not load values into %v", conf)
+	}
+}
+
+func TestExplainGroups_error(t *testing.T) {
+	s := NewMapSource(map[string]interface{}{
+		"explainconf": map[string]interface{}{"retries": "many"},
+	})
+	g, _ := Convert(&explainConf{})
+	got, err := ExplainGroups(context.Background(), s, []Group{g})
+	if err == nil {
+		t.Fatal("ExplainGroups() accepted a bad value")
+	}
+	if len(got) != 1 || got[0].Raw != "many" {
+		t.Errorf("ExplainGroups() = %v, want provenance up to the failed setting", got)
+	}
+}
+
+type explainSecretConf struct {
+	Token    string `secret:"true"`
+	Password Secret
+	Unset    string `secret:"true"`
+}
+
+func TestExplainGroups_sensitive(t *testing.T) {
+	s := &NamedSource{NameValue: "map", Source: NewMapSource(map[string]interface{}{
+		"explainsecretconf": map[string]interface{}{"token": "supersecret", "password": "hunter2"},
+	})}
+	g, _ := Convert(&explainSecretConf{})
+	got, err := ExplainGroups(context.Background(), s, []Group{g})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Provenance{
+		{Path: []string{"explainSecretConf", "Unset"}, Value: "", Sensitive: true},
+		{
+			Path: []string{"explainSecretConf", "Password"}, Value: redactedDisplay, Sensitive: true,
+			Found: true, Raw: redactedDisplay, Source: "map",
+		},
+		{
+			Path: []string{"explainSecretConf", "Token"}, Value: redactedDisplay, Sensitive: true,
+			Found: true, Raw: redactedDisplay, Source: "map",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExplainGroups() = %v, want %v", got, want)
+	}
+}
+
+func TestProvenance_String(t *testing.T) {
+	tests := []struct {
+		name string
+		p    Provenance
+		want string
+	}{
+		{
+			name: "default",
+			p:    Provenance{Path: []string{"a", "b"}, Value: 1},
+			want: "a.b = 1 (default)",
+		},
+		{
+			name: "found",
+			p:    Provenance{Path: []string{"a", "b"}, Value: 1, Found: true, Raw: "1", Source: "env"},
+			want: `a.b = 1 (from env, raw "1")`,
+		},
+		{
+			name: "expanded",
+			p: Provenance{
+				Path: []string{"a"}, Value: "x", Found: true, Raw: "x",
+				Expansions: []string{"${B}", "${C}"},
+			},
+			want: `a = x (from unnamed source, raw "x", via ${B} -> ${C})`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.p.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
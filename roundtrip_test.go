@@ -0,0 +1,260 @@
+package settings
+
+import (
+	"context"
+	"math"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type roundTripInner struct {
+	Names  []string
+	Labels map[string]string
+}
+
+type roundTripConf struct {
+	String    string
+	Bool      bool
+	Int       int
+	Int8      int8
+	Int16     int16
+	Int32     int32
+	Int64     int64
+	Uint      uint
+	Uint8     uint8
+	Uint16    uint16
+	Uint32    uint32
+	Uint64    uint64
+	Float32   float32
+	Float64   float64
+	Duration  time.Duration
+	Time      time.Time
+	Date      time.Time `layout:"DateOnly"`
+	Size      ByteSize
+	IP        net.IP
+	Password  Secret
+	Strings   []string
+	Listed    []string `separator:","`
+	Bools     []bool
+	Ints      []int
+	Int8s     []int8
+	Int16s    []int16
+	Int32s    []int32
+	Int64s    []int64
+	Uints     []uint
+	Uint8s    []uint8
+	Uint16s   []uint16
+	Uint32s   []uint32
+	Uint64s   []uint64
+	Float32s  []float32
+	Floats    []float64
+	Durations []time.Duration
+	Times     []time.Time
+	Labels    map[string]string
+	Groups    map[string][]string
+	Counts    map[string]int
+	Anything  map[string]interface{}
+	Pointer   *string
+	Optional  Optional[string]
+	Missing   *int
+	Unset     Optional[int]
+	Inner     *roundTripInner
+}
+
+func newRoundTripConf(s string, ss []string) *roundTripConf {
+	c := &roundTripConf{
+		String:    s,
+		Bool:      true,
+		Int:       math.MinInt32,
+		Int8:      math.MinInt8,
+		Int16:     math.MaxInt16,
+		Int32:     math.MinInt32,
+		Int64:     math.MaxInt64,
+		Uint:      math.MaxUint32,
+		Uint8:     math.MaxUint8,
+		Uint16:    math.MaxUint16,
+		Uint32:    math.MaxUint32,
+		Uint64:    math.MaxUint64,
+		Float32:   0.1,
+		Float64:   -1.5e21,
+		Duration:  90 * time.Minute,
+		Time:      time.Date(2020, time.March, 1, 12, 30, 15, 500, time.UTC),
+		Date:      time.Date(2020, time.March, 1, 0, 0, 0, 0, time.UTC),
+		Size:      3 * Mebibyte,
+		IP:        net.ParseIP("10.0.0.1"),
+		Strings:   ss,
+		Listed:    ss,
+		Bools:     []bool{true, false},
+		Ints:      []int{-1, 0, 1},
+		Int8s:     []int8{math.MinInt8, math.MaxInt8},
+		Int16s:    []int16{math.MinInt16, math.MaxInt16},
+		Int32s:    []int32{math.MinInt32, math.MaxInt32},
+		Int64s:    []int64{math.MinInt64, math.MaxInt64},
+		Uints:     []uint{0, math.MaxUint32},
+		Uint8s:    []uint8{0, math.MaxUint8},
+		Uint16s:   []uint16{0, math.MaxUint16},
+		Uint32s:   []uint32{0, math.MaxUint32},
+		Uint64s:   []uint64{0, math.MaxUint64},
+		Float32s:  []float32{0.1, -2},
+		Floats:    []float64{0.5, 2},
+		Durations: []time.Duration{time.Second, time.Hour},
+		Times:     []time.Time{time.Date(1999, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		Labels:    make(map[string]string),
+		Groups:    make(map[string][]string),
+		Counts:    map[string]int{"one": 1, "two": 2},
+		Anything:  map[string]interface{}{"name": s, "enabled": true},
+		Pointer:   &s,
+		Inner:     &roundTripInner{Names: ss, Labels: make(map[string]string)},
+	}
+	// Map keys are lower case because YAML and JSON sources ignore the case
+	// of keys. An empty list in a map loads as nil so it is left out.
+	for _, v := range ss {
+		c.Labels[strings.ToLower(v)] = s
+		c.Inner.Labels[strings.ToLower(s)] = v
+	}
+	if len(ss) > 0 {
+		c.Groups["all"] = ss
+	}
+	c.Optional.Set(s)
+	// Secrets are always rendered as their zero value and Missing and Unset
+	// are never given a value so that they must be left out of examples.
+	return c
+}
+
+// roundTripValues returns configurations with values that are difficult to
+// render, such as strings containing quotes, separators, or whitespace.
+func roundTripValues() map[string]*roundTripConf {
+	return map[string]*roundTripConf{
+		"plain": newRoundTripConf("value", []string{"one", "two"}),
+		"empty": newRoundTripConf("", []string{}),
+		"quotes": newRoundTripConf(
+			`say "hi" to 'them'`,
+			[]string{`"quoted"`, `it's`},
+		),
+		"escapes": newRoundTripConf(
+			`C:\path\ $HOME `+"`whoami`"+` ${`,
+			[]string{`back\slash`, `$HOME`, "`tick`"},
+		),
+		"separators": newRoundTripConf(
+			"a b, c=d; e",
+			[]string{"a b", "c,d", "e=f", " padded ", ""},
+		),
+		"syntax": newRoundTripConf(
+			"key: value # not a comment",
+			[]string{"- item", "[not, a, list]", "{not: a map}", "true", "null", "1"},
+		),
+		"unicode": newRoundTripConf(
+			"héllo ✓\ttab",
+			[]string{"日本", "emoji 🎉"},
+		),
+		"unset": func() *roundTripConf {
+			c := newRoundTripConf("value", []string{"one"})
+			c.Pointer = nil
+			c.Optional = Optional[string]{}
+			return c
+		}(),
+	}
+}
+
+func loadRoundTrip(t *testing.T, s Source) *roundTripConf {
+	t.Helper()
+	got := &roundTripConf{Inner: &roundTripInner{}}
+	g, err := Convert(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadGroups(context.Background(), s, []Group{g}); err != nil {
+		t.Fatal(err)
+	}
+	return got
+}
+
+// compareRoundTrip reports every field that did not survive the round trip.
+func compareRoundTrip(t *testing.T, got *roundTripConf, want *roundTripConf, text string) {
+	t.Helper()
+	gv, wv := reflect.ValueOf(got).Elem(), reflect.ValueOf(want).Elem()
+	for x := 0; x < gv.NumField(); x = x + 1 {
+		if !reflect.DeepEqual(gv.Field(x).Interface(), wv.Field(x).Interface()) {
+			t.Errorf("%s = %#v, want %#v", gv.Type().Field(x).Name, gv.Field(x).Interface(), wv.Field(x).Interface())
+		}
+	}
+	if t.Failed() {
+		t.Logf("rendered:\n%s", text)
+	}
+}
+
+// parseEnvExample reads ENV vars from example text the same way a shell
+// reads double quoted assignments.
+func parseEnvExample(t *testing.T, text string) []string {
+	t.Helper()
+	var env []string
+	for _, line := range strings.Split(text, "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		value := parts[1]
+		if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+			t.Fatalf("ENV value is not double quoted: %s", line)
+		}
+		value = value[1 : len(value)-1]
+		var b strings.Builder
+		for x := 0; x < len(value); x = x + 1 {
+			if value[x] == '\\' && x+1 < len(value) && strings.IndexByte("\\\"$`", value[x+1]) >= 0 {
+				x = x + 1
+			}
+			_ = b.WriteByte(value[x])
+		}
+		env = append(env, parts[0]+"="+b.String())
+	}
+	return env
+}
+
+func TestRoundTrip_yaml(t *testing.T) {
+	values := roundTripValues()
+	values["multiline"] = newRoundTripConf("first\nsecond\r\n", []string{"a\nb"})
+	for name, want := range values {
+		t.Run(name, func(t *testing.T) {
+			text := ExampleYamlGroups(convertGroups(t, want))
+			s, err := NewYAMLSource([]byte(text))
+			if err != nil {
+				t.Fatalf("NewYAMLSource() error = %v\n%s", err, text)
+			}
+			compareRoundTrip(t, loadRoundTrip(t, s), want, text)
+		})
+	}
+}
+
+func TestRoundTrip_env(t *testing.T) {
+	for name, want := range roundTripValues() {
+		t.Run(name, func(t *testing.T) {
+			text := ExampleEnvGroups(convertGroups(t, want))
+			s, err := NewEnvSource(parseEnvExample(t, text))
+			if err != nil {
+				t.Fatal(err)
+			}
+			compareRoundTrip(t, loadRoundTrip(t, s), want, text)
+		})
+	}
+}
+
+func TestRoundTrip_json(t *testing.T) {
+	values := roundTripValues()
+	values["multiline"] = newRoundTripConf("first\nsecond\r\n", []string{"a\nb"})
+	for name, want := range values {
+		t.Run(name, func(t *testing.T) {
+			text, err := ExampleJSONGroups(convertGroups(t, want), WithJSONComments())
+			if err != nil {
+				t.Fatal(err)
+			}
+			s, err := NewJSONSource([]byte(text))
+			if err != nil {
+				t.Fatalf("NewJSONSource() error = %v\n%s", err, text)
+			}
+			compareRoundTrip(t, loadRoundTrip(t, s), want, text)
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package settings
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// jsonSchemaDraft is the version of JSON Schema that is generated.
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// Patterns for the text forms of types that are parsed from strings.
+const (
+	durationPattern = `^[-+]?([0-9]*(\.[0-9]*)?(ns|us|µs|ms|s|m|h))+$|^0$`
+	byteSizePattern = `^\s*[0-9]*\.?[0-9]+\s*([kKmMgGtTpP][iI]?)?[bB]?\s*$`
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	byteSizeType = reflect.TypeOf(ByteSize(0))
+)
+
+// intRanges contains the limits of the sized integer types.
+var intRanges = map[reflect.Kind][2]float64{
+	reflect.Int8:   {math.MinInt8, math.MaxInt8},
+	reflect.Int16:  {math.MinInt16, math.MaxInt16},
+	reflect.Int32:  {math.MinInt32, math.MaxInt32},
+	reflect.Uint8:  {0, math.MaxUint8},
+	reflect.Uint16: {0, math.MaxUint16},
+	reflect.Uint32: {0, math.MaxUint32},
+	reflect.Uint:   {0, -1},
+	reflect.Uint64: {0, -1},
+}
+
+// typeSchema describes the values accepted for a type. Types that are
+// parsed from text are described as strings.
+func typeSchema(t reflect.Type) map[string]interface{} {
+	switch {
+	case t == nil:
+		return map[string]interface{}{}
+	case hasConverter(t):
+		return map[string]interface{}{"type": "string"}
+	case t == durationType:
+		return map[string]interface{}{"type": "string", "pattern": durationPattern}
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t == byteSizeType:
+		return map[string]interface{}{"type": []string{"string", "integer"}, "pattern": byteSizePattern, "minimum": 0}
+	case t == secretType, isTextType(t), isTextMarshaler(t):
+		return map[string]interface{}{"type": "string"}
+	default:
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		s := map[string]interface{}{"type": "integer"}
+		if r, ok := intRanges[t.Kind()]; ok {
+			s["minimum"] = r[0]
+			if r[1] >= 0 {
+				s["maximum"] = r[1]
+			}
+		}
+		return s
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		// Lists may also be given as a single string of separated values.
+		return map[string]interface{}{"type": []string{"array", "string"}, "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		// Maps may also be given as a string of key=value pairs.
+		return map[string]interface{}{"type": []string{"object", "string"}, "additionalProperties": typeSchema(t.Elem())}
+	case reflect.Ptr:
+		return typeSchema(t.Elem())
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// settingSchema describes a single setting including its description and
+// default value. Sensitive settings never include a default.
+func settingSchema(s Setting) map[string]interface{} {
+	v := s.Value()
+	schema := typeSchema(reflect.TypeOf(v))
+	if tf, ok := elementOf(s).(TimeFormatted); ok && len(tf.Layouts()) > 0 {
+		// Custom layouts are not described by the date-time format.
+		delete(schema, "format")
+	}
+	if s.Description() != "" {
+		schema["description"] = s.Description()
+	}
+	if isSensitive(s) {
+		schema["writeOnly"] = true
+		return schema
+	}
+	if v == nil || isNilValue(v) {
+		return schema
+	}
+	if text, ok := formatTime(s, v); ok {
+		schema["default"] = text
+		return schema
+	}
+	schema["default"] = dumpValue(v)
+	return schema
+}
+
+// groupSchema describes a group as an object with a property for each of
+// its settings and sub-trees.
+func groupSchema(g Group) map[string]interface{} {
+	properties := make(map[string]interface{})
+	var required []string
+	for _, s := range g.Settings() {
+		properties[strings.ToLower(s.Name())] = settingSchema(s)
+		if isRequired(s) {
+			required = append(required, strings.ToLower(s.Name()))
+		}
+	}
+	for _, sub := range g.Groups() {
+		properties[strings.ToLower(sub.Name())] = groupSchema(sub)
+	}
+	if pg, ok := g.(*PluginGroup); ok {
+		ts := properties[pluginTypeName].(map[string]interface{})
+		ts["enum"] = append([]string{""}, pg.optionNames()...)
+	}
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	if g.Description() != "" {
+		schema["description"] = g.Description()
+	}
+	return schema
+}
+
+// JSONSchemaGroups renders a JSON Schema document that describes the
+// configuration accepted by the given groups. The schema may be used to
+// validate YAML or JSON files before they are loaded. Property names are
+// the lower case names used for lookups. Every setting has a type derived
+// from its value, a description, and a default unless it is sensitive.
+// Required settings are listed as required properties of their group.
+func JSONSchemaGroups(groups []Group) (string, error) {
+	properties := make(map[string]interface{}, len(groups))
+	for _, g := range groups {
+		properties[strings.ToLower(g.Name())] = groupSchema(g)
+	}
+	schema := map[string]interface{}{
+		"$schema":    jsonSchemaDraft,
+		"type":       "object",
+		"properties": properties,
+	}
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(schema); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,165 @@
+package settings
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/andreyvit/diff"
+)
+
+type schemaInner struct {
+	Token Secret `description:"api token"`
+}
+
+func (*schemaInner) Name() string { return "auth" }
+
+type schemaConf struct {
+	Host    string        `description:"server host"`
+	Port    uint16        `description:"server port"`
+	Timeout time.Duration `description:"request timeout"`
+	Tags    []string
+	Since   time.Time `layout:"DateOnly"`
+	Limit   *int
+	Auth    *schemaInner
+}
+
+func TestJSONSchemaGroups(t *testing.T) {
+	g, err := Convert(&schemaConf{
+		Host:    "localhost",
+		Port:    8080,
+		Timeout: time.Second,
+		Tags:    []string{"a"},
+		Since:   time.Date(2020, time.March, 1, 0, 0, 0, 0, time.UTC),
+		Auth:    &schemaInner{Token: "hunter2"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "properties": {
+    "schemaconf": {
+      "properties": {
+        "auth": {
+          "properties": {
+            "token": {
+              "description": "api token",
+              "type": "string",
+              "writeOnly": true
+            }
+          },
+          "type": "object"
+        },
+        "host": {
+          "default": "localhost",
+          "description": "server host",
+          "type": "string"
+        },
+        "limit": {
+          "type": "integer"
+        },
+        "port": {
+          "default": 8080,
+          "description": "server port",
+          "maximum": 65535,
+          "minimum": 0,
+          "type": "integer"
+        },
+        "since": {
+          "default": "2020-03-01",
+          "type": "string"
+        },
+        "tags": {
+          "default": [
+            "a"
+          ],
+          "items": {
+            "type": "string"
+          },
+          "type": [
+            "array",
+            "string"
+          ]
+        },
+        "timeout": {
+          "default": "1s",
+          "description": "request timeout",
+          "pattern": "^[-+]?([0-9]*(\\.[0-9]*)?(ns|us|µs|ms|s|m|h))+$|^0$",
+          "type": "string"
+        }
+      },
+      "type": "object"
+    }
+  },
+  "type": "object"
+}
+`
+	got, err := JSONSchemaGroups([]Group{g})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("JSONSchemaGroups() = %v, want %v\n%s", got, want, diff.LineDiff(got, want))
+	}
+}
+
+func TestJSONSchemaGroups_plugin(t *testing.T) {
+	registerTestCaches(t)
+	g, err := Convert(&pluginConf{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := JSONSchemaGroups([]Group{g})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schema struct {
+		Properties map[string]struct {
+			Properties map[string]struct {
+				Properties map[string]struct {
+					Enum []string `json:"enum"`
+				} `json:"properties"`
+			} `json:"properties"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal([]byte(out), &schema); err != nil {
+		t.Fatal(err)
+	}
+	cache := schema.Properties["pluginconf"].Properties["cache"]
+	if got, want := cache.Properties["type"].Enum, []string{"", "memory", "redis"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("enum = %v, want %v", got, want)
+	}
+	if _, ok := cache.Properties["redis"]; !ok {
+		t.Error("missing the settings of the redis option")
+	}
+}
+
+func Test_typeSchema(t *testing.T) {
+	tests := []struct {
+		name string
+		v    interface{}
+		want string
+	}{
+		{name: "bool", v: true, want: `{"type":"boolean"}`},
+		{name: "int8", v: int8(0), want: `{"maximum":127,"minimum":-128,"type":"integer"}`},
+		{name: "uint", v: uint(0), want: `{"minimum":0,"type":"integer"}`},
+		{name: "float", v: 1.5, want: `{"type":"number"}`},
+		{name: "time", v: time.Time{}, want: `{"format":"date-time","type":"string"}`},
+		{name: "text", v: testLevel(0), want: `{"type":"string"}`},
+		{name: "map", v: map[string]bool{}, want: `{"additionalProperties":{"type":"boolean"},"type":["object","string"]}`},
+		{name: "interface", v: map[string]interface{}{}, want: `{"additionalProperties":{},"type":["object","string"]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(typeSchema(reflect.TypeOf(tt.v)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.want {
+				t.Errorf("typeSchema() = %s, want %s", b, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// secretMask is displayed in place of any non-empty Secret.
+const secretMask = "******"
+
+// Secret is a string that hides its content from every form of formatting
+// and marshaling so that it cannot be logged by accident. The only way to
+// access the content is through Reveal. An empty Secret is displayed as an
+// empty string so that a missing value can still be identified.
+type Secret string
+
+// Reveal returns the actual content of the secret.
+func (s Secret) Reveal() string {
+	return string(s)
+}
+
+// String returns a mask in place of the secret.
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return secretMask
+}
+
+// GoString returns a mask in place of the secret for the %#v verb.
+func (s Secret) GoString() string {
+	return fmt.Sprintf("%q", s.String())
+}
+
+// Format writes a mask in place of the secret for every verb.
+func (s Secret) Format(f fmt.State, verb rune) {
+	if (verb == 'v' && f.Flag('#')) || verb == 'q' {
+		_, _ = io.WriteString(f, s.GoString())
+		return
+	}
+	_, _ = io.WriteString(f, s.String())
+}
+
+// MarshalJSON renders a mask in place of the secret.
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// MarshalYAML renders a mask in place of the secret.
+func (s Secret) MarshalYAML() (interface{}, error) {
+	return s.String(), nil
+}
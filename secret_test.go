@@ -0,0 +1,95 @@
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestSecret_masking(t *testing.T) {
+	s := Secret("hunter2")
+	outputs := map[string]string{
+		"String":   s.String(),
+		"GoString": s.GoString(),
+		"%v":       fmt.Sprintf("%v", s),
+		"%s":       fmt.Sprintf("%s", s),
+		"%q":       fmt.Sprintf("%q", s),
+		"%#v":      fmt.Sprintf("%#v", s),
+		"%x":       fmt.Sprintf("%x", s),
+		"%+v":      fmt.Sprintf("%+v", struct{ S Secret }{S: s}),
+		"Println":  fmt.Sprintln(s),
+	}
+	b, err := json.Marshal(struct{ S Secret }{S: s})
+	if err != nil {
+		t.Fatal(err)
+	}
+	outputs["json"] = string(b)
+	b, err = yaml.Marshal(struct{ S Secret }{S: s})
+	if err != nil {
+		t.Fatal(err)
+	}
+	outputs["yaml"] = string(b)
+	for name, out := range outputs {
+		if strings.Contains(out, "hunter2") {
+			t.Errorf("%s revealed the secret: %s", name, out)
+		}
+		if !strings.Contains(out, secretMask) {
+			t.Errorf("%s did not mask the secret: %s", name, out)
+		}
+	}
+	if s.Reveal() != "hunter2" {
+		t.Errorf("Reveal() = %s, want hunter2", s.Reveal())
+	}
+	if Secret("").String() != "" {
+		t.Errorf("String() masked an empty secret")
+	}
+}
+
+func TestSecretSetting(t *testing.T) {
+	s := NewSecretSetting("password", "the password", "default")
+	if !s.Sensitive() {
+		t.Error("Sensitive() = false, want true")
+	}
+	if err := s.SetValue("hunter2"); err != nil {
+		t.Fatal(err)
+	}
+	if v := s.Value().(Secret); v.Reveal() != "hunter2" {
+		t.Errorf("Value() = %s, want hunter2", v.Reveal())
+	}
+	if err := s.SetValue(Secret("other")); err != nil {
+		t.Fatal(err)
+	}
+	if v := s.Value().(Secret); v.Reveal() != "other" {
+		t.Errorf("Value() = %s, want other", v.Reveal())
+	}
+	if err := s.SetValue(make(map[string]interface{})); err == nil {
+		t.Error("SetValue() accepted bad input as good")
+	}
+}
+
+func TestSecret_examples(t *testing.T) {
+	conf := &(struct {
+		Password Secret `description:"the password"`
+		Token    string `secret:"true"`
+	}{Password: "hunter2", Token: "abc123"})
+	g, err := Convert(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range g.Settings() {
+		if !isSensitive(s) {
+			t.Errorf("setting %s is not sensitive", s.Name())
+		}
+	}
+	for name, out := range map[string]string{
+		"yaml": ExampleYamlSettings(g.Settings()),
+		"env":  ExampleEnvSettings(g.Settings()),
+	} {
+		if strings.Contains(out, "hunter2") || strings.Contains(out, "abc123") {
+			t.Errorf("%s example revealed a secret default: %s", name, out)
+		}
+	}
+}
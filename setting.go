@@ -1,7 +1,11 @@
 package settings
 
 import (
+	"encoding"
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/spf13/cast"
@@ -55,11 +59,39 @@ func (g *SettingGroup) Settings() []Setting {
 	return g.SettingValues
 }
 
+// Sensitive is an optional interface for settings that contain secrets.
+// Settings that report themselves as sensitive have their values masked
+// whenever the configuration is dumped for display.
+type Sensitive interface {
+	Sensitive() bool
+}
+
+// Required is an optional interface for settings that must be given a
+// value by a source. Loading fails if a required setting is not found.
+type Required interface {
+	Required() bool
+}
+
+// Separated is an optional interface for slice settings that split text
+// into elements using a separator other than whitespace.
+type Separated interface {
+	Separator() string
+}
+
+// TimeFormatted is an optional interface for time settings that parse and
+// render text using specific layouts or a specific time zone.
+type TimeFormatted interface {
+	Layouts() []string
+	Location() *time.Location
+}
+
 // BaseSetting implements the name and description aspects of
 // any given setting.
 type BaseSetting struct {
 	NameValue        string
 	DescriptionValue string
+	SensitiveValue   bool
+	RequiredValue    bool
 }
 
 // Name returns the setting name as it appears in configuration.
@@ -72,7 +104,356 @@ func (s *BaseSetting) Description() string {
 	return s.DescriptionValue
 }
 
-// StringSetting manages an instance of string
+// Sensitive returns true if the setting contains a secret.
+func (s *BaseSetting) Sensitive() bool {
+	return s.SensitiveValue
+}
+
+// Required returns true if a source must have a value for the setting.
+func (s *BaseSetting) Required() bool {
+	return s.RequiredValue
+}
+
+// TypedSetting manages an instance of T. Raw values from a Source are
+// converted into T by the Cast function. If Cast is nil then the built-in
+// conversion for T is used. Every <Type>Setting in this package loads
+// values through a TypedSetting with the built-in conversion. Older types
+// keep their own exported fields while newer ones are aliases of it.
+type TypedSetting[T any] struct {
+	*BaseSetting
+	TypedValue     *T
+	Cast           func(interface{}) (T, error)
+	SeparatorValue string
+}
+
+// NewTypedSetting creates a TypedSetting with the given default value and
+// conversion. A nil cast selects the built-in conversion for T.
+func NewTypedSetting[T any](name string, description string, fallback T, cast func(interface{}) (T, error)) *TypedSetting[T] {
+	return &TypedSetting[T]{
+		BaseSetting: &BaseSetting{
+			NameValue:        name,
+			DescriptionValue: description,
+		},
+		TypedValue: &fallback,
+		Cast:       cast,
+	}
+}
+
+// Value returns the underlying T.
+func (s *TypedSetting[T]) Value() interface{} {
+	return *s.TypedValue
+}
+
+func (s *TypedSetting[T]) detach() Setting {
+	v := *s.TypedValue
+	c := *s
+	c.TypedValue = &v
+	return &c
+}
+
+// Separator returns the string used to split text into the elements of a
+// slice. An empty separator splits text on whitespace.
+func (s *TypedSetting[T]) Separator() string {
+	return s.SeparatorValue
+}
+
+func (s *TypedSetting[T]) setSeparator(sep string) {
+	s.SeparatorValue = sep
+}
+
+// SetValue changes the underlying T.
+func (s *TypedSetting[T]) SetValue(v interface{}) error {
+	if str, ok := v.(string); ok && s.SeparatorValue != "" && !isJSONList(str) &&
+		reflect.TypeOf((*T)(nil)).Elem().Kind() == reflect.Slice {
+		v = splitList(str, s.SeparatorValue)
+	}
+	c := s.Cast
+	if c == nil {
+		// Values that already have the correct type need no conversion.
+		if tv, ok := v.(T); ok {
+			*s.TypedValue = tv
+			return nil
+		}
+		var ok bool
+		if c, ok = builtinCast[T](); !ok {
+			return fmt.Errorf("no conversion available for %T", *s.TypedValue)
+		}
+	}
+	tv, err := c(v)
+	if err != nil {
+		return err
+	}
+	*s.TypedValue = tv
+	return nil
+}
+
+// builtinType describes a type that the library supports without any
+// additional configuration.
+type builtinType struct {
+	typ  reflect.Type
+	cast interface{}
+	bind func(base *BaseSetting, v reflect.Value) Setting
+}
+
+// builtin creates the description of a built-in type from its conversion
+// and a function that wraps a pointer to the value in the exported setting
+// for the type. A nil wrap manages the value with a TypedSetting so that
+// supporting a new type only requires adding it to builtinTypes.
+func builtin[T any](c func(interface{}) (T, error), wrap func(base *BaseSetting, v *T) Setting) builtinType {
+	if wrap == nil {
+		wrap = func(base *BaseSetting, v *T) Setting {
+			return &TypedSetting[T]{BaseSetting: base, TypedValue: v}
+		}
+	}
+	return builtinType{
+		typ:  reflect.TypeOf((*T)(nil)).Elem(),
+		cast: c,
+		bind: func(base *BaseSetting, v reflect.Value) Setting {
+			return wrap(base, v.Addr().Interface().(*T))
+		},
+	}
+}
+
+func newBuiltinTypes(types ...builtinType) map[reflect.Type]builtinType {
+	m := make(map[reflect.Type]builtinType, len(types))
+	for _, t := range types {
+		m[t.typ] = t
+	}
+	return m
+}
+
+var builtinTypes = newBuiltinTypes(
+	builtin(cast.ToStringE, func(b *BaseSetting, v *string) Setting {
+		return &StringSetting{BaseSetting: b, StringValue: v}
+	}),
+	builtin(cast.ToBoolE, func(b *BaseSetting, v *bool) Setting {
+		return &BoolSetting{BaseSetting: b, BoolValue: v}
+	}),
+	builtin(cast.ToIntE, func(b *BaseSetting, v *int) Setting {
+		return &IntSetting{BaseSetting: b, IntValue: v}
+	}),
+	builtin(cast.ToInt8E, func(b *BaseSetting, v *int8) Setting {
+		return &Int8Setting{BaseSetting: b, Int8Value: v}
+	}),
+	builtin(cast.ToInt16E, func(b *BaseSetting, v *int16) Setting {
+		return &Int16Setting{BaseSetting: b, Int16Value: v}
+	}),
+	builtin(cast.ToInt32E, func(b *BaseSetting, v *int32) Setting {
+		return &Int32Setting{BaseSetting: b, Int32Value: v}
+	}),
+	builtin(cast.ToInt64E, func(b *BaseSetting, v *int64) Setting {
+		return &Int64Setting{BaseSetting: b, Int64Value: v}
+	}),
+	builtin(cast.ToUintE, func(b *BaseSetting, v *uint) Setting {
+		return &UintSetting{BaseSetting: b, UintValue: v}
+	}),
+	builtin(cast.ToUint8E, func(b *BaseSetting, v *uint8) Setting {
+		return &Uint8Setting{BaseSetting: b, Uint8Value: v}
+	}),
+	builtin(cast.ToUint16E, func(b *BaseSetting, v *uint16) Setting {
+		return &Uint16Setting{BaseSetting: b, Uint16Value: v}
+	}),
+	builtin(cast.ToUint32E, func(b *BaseSetting, v *uint32) Setting {
+		return &Uint32Setting{BaseSetting: b, Uint32Value: v}
+	}),
+	builtin(cast.ToUint64E, func(b *BaseSetting, v *uint64) Setting {
+		return &Uint64Setting{BaseSetting: b, Uint64Value: v}
+	}),
+	builtin(cast.ToFloat32E, func(b *BaseSetting, v *float32) Setting {
+		return &Float32Setting{BaseSetting: b, Float32Value: v}
+	}),
+	builtin(cast.ToFloat64E, func(b *BaseSetting, v *float64) Setting {
+		return &Float64Setting{BaseSetting: b, Float64Value: v}
+	}),
+	builtin(cast.ToTimeE, func(b *BaseSetting, v *time.Time) Setting {
+		return &TimeSetting{BaseSetting: b, TimeValue: v}
+	}),
+	builtin(cast.ToDurationE, func(b *BaseSetting, v *time.Duration) Setting {
+		return &DurationSetting{BaseSetting: b, DurationValue: v}
+	}),
+	builtin(castSecret, func(b *BaseSetting, v *Secret) Setting {
+		return &SecretSetting{BaseSetting: b, SecretValue: v}
+	}),
+	builtin(castByteSize, nil),
+	builtin(castSlice("string", cast.ToStringE), func(b *BaseSetting, v *[]string) Setting {
+		return &StringSliceSetting{BaseSetting: b, StringSliceValue: v}
+	}),
+	builtin(castSlice("bool", cast.ToBoolE), func(b *BaseSetting, v *[]bool) Setting {
+		return &BoolSliceSetting{BaseSetting: b, BoolSliceValue: v}
+	}),
+	builtin(castSlice("int", cast.ToIntE), func(b *BaseSetting, v *[]int) Setting {
+		return &IntSliceSetting{BaseSetting: b, IntSliceValue: v}
+	}),
+	builtin(castSlice("int8", cast.ToInt8E), nil),
+	builtin(castSlice("int16", cast.ToInt16E), nil),
+	builtin(castSlice("int32", cast.ToInt32E), nil),
+	builtin(castSlice("int64", cast.ToInt64E), nil),
+	builtin(castSlice("uint", cast.ToUintE), nil),
+	builtin(castSlice("uint8", cast.ToUint8E), nil),
+	builtin(castSlice("uint16", cast.ToUint16E), nil),
+	builtin(castSlice("uint32", cast.ToUint32E), nil),
+	builtin(castSlice("uint64", cast.ToUint64E), nil),
+	builtin(castSlice("float32", cast.ToFloat32E), nil),
+	builtin(castSlice("float64", cast.ToFloat64E), nil),
+	builtin(castSlice("time", cast.ToTimeE), nil),
+	builtin(castSlice("duration", cast.ToDurationE), func(b *BaseSetting, v *[]time.Duration) Setting {
+		return &DurationSliceSetting{BaseSetting: b, DurationSliceValue: v}
+	}),
+	builtin(castStringMap(cast.ToStringMapStringSliceE), func(b *BaseSetting, v *map[string][]string) Setting {
+		return &StringMapStringSliceSetting{BaseSetting: b, StringMapStringSliceValue: v}
+	}),
+	builtin(castStringMap(cast.ToStringMapStringE), func(b *BaseSetting, v *map[string]string) Setting {
+		return &StringMapStringSetting{BaseSetting: b, StringMapStringValue: v}
+	}),
+)
+
+func builtinCast[T any]() (func(interface{}) (T, error), bool) {
+	b, ok := builtinTypes[reflect.TypeOf((*T)(nil)).Elem()]
+	if !ok {
+		return nil, false
+	}
+	c, ok := b.cast.(func(interface{}) (T, error))
+	return c, ok
+}
+
+// isJSONList returns true if text should be parsed as a JSON array rather
+// than split into elements.
+func isJSONList(s string) bool {
+	return strings.HasPrefix(strings.TrimSpace(s), "[")
+}
+
+// castSlice builds the conversion for a slice from the conversion of its
+// elements. Slices, such as those from YAML or JSON arrays, have each element
+// converted. Strings, such as those from ENV, are split on whitespace unless
+// they contain a JSON array, which allows elements that contain whitespace.
+func castSlice[T any](name string, c func(interface{}) (T, error)) func(interface{}) ([]T, error) {
+	return func(v interface{}) ([]T, error) {
+		if str, ok := v.(string); ok && isJSONList(str) {
+			var list []interface{}
+			if err := json.Unmarshal([]byte(str), &list); err != nil {
+				return nil, fmt.Errorf("%s slice parsing failed at interim JSON step: %s", name, err.Error())
+			}
+			v = list
+		}
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			tmp, err := cast.ToStringSliceE(v)
+			if err != nil {
+				return nil, fmt.Errorf("%s slice parsing failed at interim string step: %s", name, err.Error())
+			}
+			rv = reflect.ValueOf(tmp)
+		}
+		result := make([]T, 0, rv.Len())
+		for x := 0; x < rv.Len(); x = x + 1 {
+			tv, err := c(rv.Index(x).Interface())
+			if err != nil {
+				return nil, fmt.Errorf("%s slice parsing failed at element %d: %s", name, x, err.Error())
+			}
+			result = append(result, tv)
+		}
+		return result, nil
+	}
+}
+
+// splitList separates text into elements using the given separator and
+// removes any whitespace surrounding each element.
+func splitList(s string, sep string) []string {
+	if strings.TrimSpace(s) == "" {
+		return []string{}
+	}
+	parts := strings.Split(s, sep)
+	for x := range parts {
+		parts[x] = strings.TrimSpace(parts[x])
+	}
+	return parts
+}
+
+// castStringMap adds support for maps encoded as "k1=v1,k2=v2" strings to
+// a map conversion from the cast library.
+func castStringMap[T any](c func(interface{}) (T, error)) func(interface{}) (T, error) {
+	return func(v interface{}) (T, error) {
+		if str, ok := v.(string); ok && !strings.HasPrefix(strings.TrimSpace(str), "{") {
+			m, err := splitPairs(str)
+			if err != nil {
+				var empty T
+				return empty, err
+			}
+			v = m
+		}
+		return c(v)
+	}
+}
+
+// splitPairs parses a map encoded as "k1=v1,k2=v2".
+func splitPairs(s string) (map[string]interface{}, error) {
+	m := make(map[string]interface{})
+	if strings.TrimSpace(s) == "" {
+		return m, nil
+	}
+	for _, pair := range strings.Split(s, ",") {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("map entry %q is not formatted as key=value", pair)
+		}
+		m[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return m, nil
+}
+
+// rawMap converts any map with string keys, a JSON object, or a string of
+// "k1=v1,k2=v2" pairs into a map of raw values.
+func rawMap(v interface{}) (map[string]interface{}, error) {
+	if str, ok := v.(string); ok {
+		if !strings.HasPrefix(strings.TrimSpace(str), "{") {
+			return splitPairs(str)
+		}
+		m := make(map[string]interface{})
+		if err := json.Unmarshal([]byte(str), &m); err != nil {
+			return nil, fmt.Errorf("failed to parse map from JSON: %s", err.Error())
+		}
+		return m, nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Map {
+		return nil, fmt.Errorf("unable to cast %#v of type %T to a map", v, v)
+	}
+	m := make(map[string]interface{}, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		k, err := cast.ToStringE(iter.Key().Interface())
+		if err != nil {
+			return nil, fmt.Errorf("map key %v is not a string", iter.Key())
+		}
+		m[k] = iter.Value().Interface()
+	}
+	return m, nil
+}
+
+func castSecret(v interface{}) (Secret, error) {
+	// A Secret must be handled before casting because the cast library
+	// would otherwise render it through the masking String method.
+	if sv, ok := v.(Secret); ok {
+		return sv, nil
+	}
+	str, err := cast.ToStringE(v)
+	return Secret(str), err
+}
+
+func castByteSize(v interface{}) (ByteSize, error) {
+	switch bv := v.(type) {
+	case ByteSize:
+		return bv, nil
+	case string:
+		return ParseByteSize(bv)
+	case []byte:
+		return ParseByteSize(string(bv))
+	default:
+	}
+	n, err := cast.ToUint64E(v)
+	return ByteSize(n), err
+}
+
+// StringSetting manages an instance of string.
 type StringSetting struct {
 	*BaseSetting
 	StringValue *string
@@ -96,12 +477,18 @@ func (s *StringSetting) Value() interface{} {
 
 // SetValue changes the underlying string.
 func (s *StringSetting) SetValue(v interface{}) error {
-	var err error
-	*s.StringValue, err = cast.ToStringE(v)
-	return err
+	return s.typed().SetValue(v)
+}
+
+func (s *StringSetting) typed() *TypedSetting[string] {
+	return &TypedSetting[string]{BaseSetting: s.BaseSetting, TypedValue: s.StringValue}
 }
 
-// BoolSetting manages an instance of bool
+func (s *StringSetting) detach() Setting {
+	return s.typed().detach()
+}
+
+// BoolSetting manages an instance of bool.
 type BoolSetting struct {
 	*BaseSetting
 	BoolValue *bool
@@ -125,18 +512,24 @@ func (s *BoolSetting) Value() interface{} {
 
 // SetValue changes the underlying bool.
 func (s *BoolSetting) SetValue(v interface{}) error {
-	var err error
-	*s.BoolValue, err = cast.ToBoolE(v)
-	return err
+	return s.typed().SetValue(v)
+}
+
+func (s *BoolSetting) typed() *TypedSetting[bool] {
+	return &TypedSetting[bool]{BaseSetting: s.BaseSetting, TypedValue: s.BoolValue}
+}
+
+func (s *BoolSetting) detach() Setting {
+	return s.typed().detach()
 }
 
-// IntSetting manages an instance of int
+// IntSetting manages an instance of int.
 type IntSetting struct {
 	*BaseSetting
 	IntValue *int
 }
 
-// NewIntSetting creates a IntSetting with the given default value.
+// NewIntSetting creates an IntSetting with the given default value.
 func NewIntSetting(name string, description string, fallback int) *IntSetting {
 	return &IntSetting{
 		BaseSetting: &BaseSetting{
@@ -154,18 +547,24 @@ func (s *IntSetting) Value() interface{} {
 
 // SetValue changes the underlying int.
 func (s *IntSetting) SetValue(v interface{}) error {
-	var err error
-	*s.IntValue, err = cast.ToIntE(v)
-	return err
+	return s.typed().SetValue(v)
 }
 
-// Int8Setting manages an instance of int8
+func (s *IntSetting) typed() *TypedSetting[int] {
+	return &TypedSetting[int]{BaseSetting: s.BaseSetting, TypedValue: s.IntValue}
+}
+
+func (s *IntSetting) detach() Setting {
+	return s.typed().detach()
+}
+
+// Int8Setting manages an instance of int8.
 type Int8Setting struct {
 	*BaseSetting
 	Int8Value *int8
 }
 
-// NewInt8Setting creates a Int8Setting with the given default value.
+// NewInt8Setting creates an Int8Setting with the given default value.
 func NewInt8Setting(name string, description string, fallback int8) *Int8Setting {
 	return &Int8Setting{
 		BaseSetting: &BaseSetting{
@@ -183,18 +582,24 @@ func (s *Int8Setting) Value() interface{} {
 
 // SetValue changes the underlying int8.
 func (s *Int8Setting) SetValue(v interface{}) error {
-	var err error
-	*s.Int8Value, err = cast.ToInt8E(v)
-	return err
+	return s.typed().SetValue(v)
 }
 
-// Int16Setting manages an instance of int16
+func (s *Int8Setting) typed() *TypedSetting[int8] {
+	return &TypedSetting[int8]{BaseSetting: s.BaseSetting, TypedValue: s.Int8Value}
+}
+
+func (s *Int8Setting) detach() Setting {
+	return s.typed().detach()
+}
+
+// Int16Setting manages an instance of int16.
 type Int16Setting struct {
 	*BaseSetting
 	Int16Value *int16
 }
 
-// NewInt16Setting creates a Int16Setting with the given default value.
+// NewInt16Setting creates an Int16Setting with the given default value.
 func NewInt16Setting(name string, description string, fallback int16) *Int16Setting {
 	return &Int16Setting{
 		BaseSetting: &BaseSetting{
@@ -212,18 +617,24 @@ func (s *Int16Setting) Value() interface{} {
 
 // SetValue changes the underlying int16.
 func (s *Int16Setting) SetValue(v interface{}) error {
-	var err error
-	*s.Int16Value, err = cast.ToInt16E(v)
-	return err
+	return s.typed().SetValue(v)
+}
+
+func (s *Int16Setting) typed() *TypedSetting[int16] {
+	return &TypedSetting[int16]{BaseSetting: s.BaseSetting, TypedValue: s.Int16Value}
 }
 
-// Int32Setting manages an instance of int32
+func (s *Int16Setting) detach() Setting {
+	return s.typed().detach()
+}
+
+// Int32Setting manages an instance of int32.
 type Int32Setting struct {
 	*BaseSetting
 	Int32Value *int32
 }
 
-// NewInt32Setting creates a Int32Setting with the given default value.
+// NewInt32Setting creates an Int32Setting with the given default value.
 func NewInt32Setting(name string, description string, fallback int32) *Int32Setting {
 	return &Int32Setting{
 		BaseSetting: &BaseSetting{
@@ -241,18 +652,24 @@ func (s *Int32Setting) Value() interface{} {
 
 // SetValue changes the underlying int32.
 func (s *Int32Setting) SetValue(v interface{}) error {
-	var err error
-	*s.Int32Value, err = cast.ToInt32E(v)
-	return err
+	return s.typed().SetValue(v)
 }
 
-// Int64Setting manages an instance of int64
+func (s *Int32Setting) typed() *TypedSetting[int32] {
+	return &TypedSetting[int32]{BaseSetting: s.BaseSetting, TypedValue: s.Int32Value}
+}
+
+func (s *Int32Setting) detach() Setting {
+	return s.typed().detach()
+}
+
+// Int64Setting manages an instance of int64.
 type Int64Setting struct {
 	*BaseSetting
 	Int64Value *int64
 }
 
-// NewInt64Setting creates a Int64Setting with the given default value.
+// NewInt64Setting creates an Int64Setting with the given default value.
 func NewInt64Setting(name string, description string, fallback int64) *Int64Setting {
 	return &Int64Setting{
 		BaseSetting: &BaseSetting{
@@ -270,18 +687,24 @@ func (s *Int64Setting) Value() interface{} {
 
 // SetValue changes the underlying int64.
 func (s *Int64Setting) SetValue(v interface{}) error {
-	var err error
-	*s.Int64Value, err = cast.ToInt64E(v)
-	return err
+	return s.typed().SetValue(v)
+}
+
+func (s *Int64Setting) typed() *TypedSetting[int64] {
+	return &TypedSetting[int64]{BaseSetting: s.BaseSetting, TypedValue: s.Int64Value}
 }
 
-// UintSetting manages an instance of uint
+func (s *Int64Setting) detach() Setting {
+	return s.typed().detach()
+}
+
+// UintSetting manages an instance of uint.
 type UintSetting struct {
 	*BaseSetting
 	UintValue *uint
 }
 
-// NewUintSetting creates a UintSetting with the given default value.
+// NewUintSetting creates an UintSetting with the given default value.
 func NewUintSetting(name string, description string, fallback uint) *UintSetting {
 	return &UintSetting{
 		BaseSetting: &BaseSetting{
@@ -299,18 +722,24 @@ func (s *UintSetting) Value() interface{} {
 
 // SetValue changes the underlying uint.
 func (s *UintSetting) SetValue(v interface{}) error {
-	var err error
-	*s.UintValue, err = cast.ToUintE(v)
-	return err
+	return s.typed().SetValue(v)
 }
 
-// Uint8Setting manages an instance of uint8
+func (s *UintSetting) typed() *TypedSetting[uint] {
+	return &TypedSetting[uint]{BaseSetting: s.BaseSetting, TypedValue: s.UintValue}
+}
+
+func (s *UintSetting) detach() Setting {
+	return s.typed().detach()
+}
+
+// Uint8Setting manages an instance of uint8.
 type Uint8Setting struct {
 	*BaseSetting
 	Uint8Value *uint8
 }
 
-// NewUint8Setting creates a Uint8Setting with the given default value.
+// NewUint8Setting creates an Uint8Setting with the given default value.
 func NewUint8Setting(name string, description string, fallback uint8) *Uint8Setting {
 	return &Uint8Setting{
 		BaseSetting: &BaseSetting{
@@ -328,18 +757,24 @@ func (s *Uint8Setting) Value() interface{} {
 
 // SetValue changes the underlying uint8.
 func (s *Uint8Setting) SetValue(v interface{}) error {
-	var err error
-	*s.Uint8Value, err = cast.ToUint8E(v)
-	return err
+	return s.typed().SetValue(v)
+}
+
+func (s *Uint8Setting) typed() *TypedSetting[uint8] {
+	return &TypedSetting[uint8]{BaseSetting: s.BaseSetting, TypedValue: s.Uint8Value}
 }
 
-// Uint16Setting manages an instance of uint16
+func (s *Uint8Setting) detach() Setting {
+	return s.typed().detach()
+}
+
+// Uint16Setting manages an instance of uint16.
 type Uint16Setting struct {
 	*BaseSetting
 	Uint16Value *uint16
 }
 
-// NewUint16Setting creates a Uint16Setting with the given default value.
+// NewUint16Setting creates an Uint16Setting with the given default value.
 func NewUint16Setting(name string, description string, fallback uint16) *Uint16Setting {
 	return &Uint16Setting{
 		BaseSetting: &BaseSetting{
@@ -357,18 +792,24 @@ func (s *Uint16Setting) Value() interface{} {
 
 // SetValue changes the underlying uint16.
 func (s *Uint16Setting) SetValue(v interface{}) error {
-	var err error
-	*s.Uint16Value, err = cast.ToUint16E(v)
-	return err
+	return s.typed().SetValue(v)
+}
+
+func (s *Uint16Setting) typed() *TypedSetting[uint16] {
+	return &TypedSetting[uint16]{BaseSetting: s.BaseSetting, TypedValue: s.Uint16Value}
+}
+
+func (s *Uint16Setting) detach() Setting {
+	return s.typed().detach()
 }
 
-// Uint32Setting manages an instance of uint32
+// Uint32Setting manages an instance of uint32.
 type Uint32Setting struct {
 	*BaseSetting
 	Uint32Value *uint32
 }
 
-// NewUint32Setting creates a Uint32Setting with the given default value.
+// NewUint32Setting creates an Uint32Setting with the given default value.
 func NewUint32Setting(name string, description string, fallback uint32) *Uint32Setting {
 	return &Uint32Setting{
 		BaseSetting: &BaseSetting{
@@ -386,18 +827,24 @@ func (s *Uint32Setting) Value() interface{} {
 
 // SetValue changes the underlying uint32.
 func (s *Uint32Setting) SetValue(v interface{}) error {
-	var err error
-	*s.Uint32Value, err = cast.ToUint32E(v)
-	return err
+	return s.typed().SetValue(v)
+}
+
+func (s *Uint32Setting) typed() *TypedSetting[uint32] {
+	return &TypedSetting[uint32]{BaseSetting: s.BaseSetting, TypedValue: s.Uint32Value}
+}
+
+func (s *Uint32Setting) detach() Setting {
+	return s.typed().detach()
 }
 
-// Uint64Setting manages an instance of uint64
+// Uint64Setting manages an instance of uint64.
 type Uint64Setting struct {
 	*BaseSetting
 	Uint64Value *uint64
 }
 
-// NewUint64Setting creates a Uint64Setting with the given default value.
+// NewUint64Setting creates an Uint64Setting with the given default value.
 func NewUint64Setting(name string, description string, fallback uint64) *Uint64Setting {
 	return &Uint64Setting{
 		BaseSetting: &BaseSetting{
@@ -415,12 +862,18 @@ func (s *Uint64Setting) Value() interface{} {
 
 // SetValue changes the underlying uint64.
 func (s *Uint64Setting) SetValue(v interface{}) error {
-	var err error
-	*s.Uint64Value, err = cast.ToUint64E(v)
-	return err
+	return s.typed().SetValue(v)
 }
 
-// Float32Setting manages an instance of float32
+func (s *Uint64Setting) typed() *TypedSetting[uint64] {
+	return &TypedSetting[uint64]{BaseSetting: s.BaseSetting, TypedValue: s.Uint64Value}
+}
+
+func (s *Uint64Setting) detach() Setting {
+	return s.typed().detach()
+}
+
+// Float32Setting manages an instance of float32.
 type Float32Setting struct {
 	*BaseSetting
 	Float32Value *float32
@@ -444,12 +897,18 @@ func (s *Float32Setting) Value() interface{} {
 
 // SetValue changes the underlying float32.
 func (s *Float32Setting) SetValue(v interface{}) error {
-	var err error
-	*s.Float32Value, err = cast.ToFloat32E(v)
-	return err
+	return s.typed().SetValue(v)
+}
+
+func (s *Float32Setting) typed() *TypedSetting[float32] {
+	return &TypedSetting[float32]{BaseSetting: s.BaseSetting, TypedValue: s.Float32Value}
 }
 
-// Float64Setting manages an instance of float64
+func (s *Float32Setting) detach() Setting {
+	return s.typed().detach()
+}
+
+// Float64Setting manages an instance of float64.
 type Float64Setting struct {
 	*BaseSetting
 	Float64Value *float64
@@ -473,15 +932,26 @@ func (s *Float64Setting) Value() interface{} {
 
 // SetValue changes the underlying float64.
 func (s *Float64Setting) SetValue(v interface{}) error {
-	var err error
-	*s.Float64Value, err = cast.ToFloat64E(v)
-	return err
+	return s.typed().SetValue(v)
 }
 
-// TimeSetting manages an instance of time.Time
+func (s *Float64Setting) typed() *TypedSetting[float64] {
+	return &TypedSetting[float64]{BaseSetting: s.BaseSetting, TypedValue: s.Float64Value}
+}
+
+func (s *Float64Setting) detach() Setting {
+	return s.typed().detach()
+}
+
+// TimeSetting manages an instance of time.Time. Text is parsed with the
+// first of the LayoutValues that matches or, if there are none, with any of
+// the formats recognized by the cast library. Times without a zone are in
+// LocationValue, or UTC if it is nil.
 type TimeSetting struct {
 	*BaseSetting
-	TimeValue *time.Time
+	TimeValue     *time.Time
+	LayoutValues  []string
+	LocationValue *time.Location
 }
 
 // NewTimeSetting creates a TimeSetting with the given default value.
@@ -495,6 +965,27 @@ func NewTimeSetting(name string, description string, fallback time.Time) *TimeSe
 	}
 }
 
+// NewLayoutTimeSetting creates a TimeSetting that parses text with the
+// given layouts.
+func NewLayoutTimeSetting(name string, description string, fallback time.Time, layouts ...string) *TimeSetting {
+	s := NewTimeSetting(name, description, fallback)
+	s.LayoutValues = layouts
+	return s
+}
+
+// Layouts returns the layouts used to parse and render text.
+func (s *TimeSetting) Layouts() []string {
+	return s.LayoutValues
+}
+
+// Location returns the time zone of times that do not include one.
+func (s *TimeSetting) Location() *time.Location {
+	if s.LocationValue == nil {
+		return time.UTC
+	}
+	return s.LocationValue
+}
+
 // Value returns the underlying time.Time.
 func (s *TimeSetting) Value() interface{} {
 	return *s.TimeValue
@@ -502,12 +993,75 @@ func (s *TimeSetting) Value() interface{} {
 
 // SetValue changes the underlying time.Time.
 func (s *TimeSetting) SetValue(v interface{}) error {
-	var err error
-	*s.TimeValue, err = cast.ToTimeE(v)
-	return err
+	str, isText := v.(string)
+	if b, ok := v.([]byte); ok {
+		str, isText = string(b), true
+	}
+	if !isText {
+		return s.typed().SetValue(v)
+	}
+	if len(s.LayoutValues) < 1 {
+		t, err := cast.ToTimeInDefaultLocationE(str, s.Location())
+		if err != nil {
+			return err
+		}
+		*s.TimeValue = t
+		return nil
+	}
+	for _, layout := range s.LayoutValues {
+		if t, err := time.ParseInLocation(layout, str, s.Location()); err == nil {
+			*s.TimeValue = t
+			return nil
+		}
+	}
+	return fmt.Errorf("time %q does not match any layout of %s", str, strings.Join(s.LayoutValues, ", "))
+}
+
+func (s *TimeSetting) typed() *TypedSetting[time.Time] {
+	return &TypedSetting[time.Time]{BaseSetting: s.BaseSetting, TypedValue: s.TimeValue}
+}
+
+func (s *TimeSetting) detach() Setting {
+	v := *s.TimeValue
+	c := *s
+	c.TimeValue = &v
+	return &c
+}
+
+// namedLayouts allows the layout constants of the time package to be
+// referenced by name.
+var namedLayouts = map[string]string{
+	"ANSIC":       time.ANSIC,
+	"UnixDate":    time.UnixDate,
+	"RubyDate":    time.RubyDate,
+	"RFC822":      time.RFC822,
+	"RFC822Z":     time.RFC822Z,
+	"RFC850":      time.RFC850,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"Kitchen":     time.Kitchen,
+	"DateTime":    time.DateTime,
+	"DateOnly":    time.DateOnly,
+	"TimeOnly":    time.TimeOnly,
+}
+
+// parseLayouts splits a list of layouts separated by "|" and replaces the
+// names of layout constants from the time package with their values.
+func parseLayouts(tag string) []string {
+	layouts := strings.Split(tag, "|")
+	for x, layout := range layouts {
+		layout = strings.TrimSpace(layout)
+		if named, ok := namedLayouts[layout]; ok {
+			layout = named
+		}
+		layouts[x] = layout
+	}
+	return layouts
 }
 
-// DurationSetting manages an instance of time.Duration
+// DurationSetting manages an instance of time.Duration.
 type DurationSetting struct {
 	*BaseSetting
 	DurationValue *time.Duration
@@ -531,15 +1085,22 @@ func (s *DurationSetting) Value() interface{} {
 
 // SetValue changes the underlying time.Duration.
 func (s *DurationSetting) SetValue(v interface{}) error {
-	var err error
-	*s.DurationValue, err = cast.ToDurationE(v)
-	return err
+	return s.typed().SetValue(v)
 }
 
-// BoolSliceSetting manages an instance of []bool
+func (s *DurationSetting) typed() *TypedSetting[time.Duration] {
+	return &TypedSetting[time.Duration]{BaseSetting: s.BaseSetting, TypedValue: s.DurationValue}
+}
+
+func (s *DurationSetting) detach() Setting {
+	return s.typed().detach()
+}
+
+// BoolSliceSetting manages an instance of []bool.
 type BoolSliceSetting struct {
 	*BaseSetting
 	BoolSliceValue *[]bool
+	SeparatorValue string
 }
 
 // NewBoolSliceSetting creates a BoolSliceSetting with the given default value.
@@ -560,20 +1121,32 @@ func (s *BoolSliceSetting) Value() interface{} {
 
 // SetValue changes the underlying []bool.
 func (s *BoolSliceSetting) SetValue(v interface{}) error {
-	var err error
-	var tmp []string
-	tmp, err = cast.ToStringSliceE(v)
-	if err != nil {
-		return fmt.Errorf("bool slice parsing failed at interim string step: %s", err.Error())
-	}
-	*s.BoolSliceValue, err = cast.ToBoolSliceE(tmp)
-	return err
+	return s.typed().SetValue(v)
+}
+
+// Separator returns the string used to split text into elements. An
+// empty separator splits text on whitespace.
+func (s *BoolSliceSetting) Separator() string {
+	return s.SeparatorValue
 }
 
-// DurationSliceSetting manages an instance of []time.Duration
+func (s *BoolSliceSetting) setSeparator(sep string) {
+	s.SeparatorValue = sep
+}
+
+func (s *BoolSliceSetting) typed() *TypedSetting[[]bool] {
+	return &TypedSetting[[]bool]{BaseSetting: s.BaseSetting, TypedValue: s.BoolSliceValue, SeparatorValue: s.SeparatorValue}
+}
+
+func (s *BoolSliceSetting) detach() Setting {
+	return s.typed().detach()
+}
+
+// DurationSliceSetting manages an instance of []time.Duration.
 type DurationSliceSetting struct {
 	*BaseSetting
 	DurationSliceValue *[]time.Duration
+	SeparatorValue     string
 }
 
 // NewDurationSliceSetting creates a DurationSliceSetting with the given default value.
@@ -594,23 +1167,35 @@ func (s *DurationSliceSetting) Value() interface{} {
 
 // SetValue changes the underlying []time.Duration.
 func (s *DurationSliceSetting) SetValue(v interface{}) error {
-	var err error
-	var tmp []string
-	tmp, err = cast.ToStringSliceE(v)
-	if err != nil {
-		return fmt.Errorf("duration slice parsing failed at interim string step: %s", err.Error())
-	}
-	*s.DurationSliceValue, err = cast.ToDurationSliceE(tmp)
-	return err
+	return s.typed().SetValue(v)
+}
+
+// Separator returns the string used to split text into elements. An
+// empty separator splits text on whitespace.
+func (s *DurationSliceSetting) Separator() string {
+	return s.SeparatorValue
 }
 
-// IntSliceSetting manages an instance of []int
+func (s *DurationSliceSetting) setSeparator(sep string) {
+	s.SeparatorValue = sep
+}
+
+func (s *DurationSliceSetting) typed() *TypedSetting[[]time.Duration] {
+	return &TypedSetting[[]time.Duration]{BaseSetting: s.BaseSetting, TypedValue: s.DurationSliceValue, SeparatorValue: s.SeparatorValue}
+}
+
+func (s *DurationSliceSetting) detach() Setting {
+	return s.typed().detach()
+}
+
+// IntSliceSetting manages an instance of []int.
 type IntSliceSetting struct {
 	*BaseSetting
-	IntSliceValue *[]int
+	IntSliceValue  *[]int
+	SeparatorValue string
 }
 
-// NewIntSliceSetting creates a IntSliceSetting with the given default value.
+// NewIntSliceSetting creates an IntSliceSetting with the given default value.
 func NewIntSliceSetting(name string, description string, fallback []int) *IntSliceSetting {
 	return &IntSliceSetting{
 		BaseSetting: &BaseSetting{
@@ -628,20 +1213,128 @@ func (s *IntSliceSetting) Value() interface{} {
 
 // SetValue changes the underlying []int.
 func (s *IntSliceSetting) SetValue(v interface{}) error {
-	var err error
-	var tmp []string
-	tmp, err = cast.ToStringSliceE(v)
-	if err != nil {
-		return fmt.Errorf("int slice parsing failed at interim string step: %s", err.Error())
-	}
-	*s.IntSliceValue, err = cast.ToIntSliceE(tmp)
-	return err
+	return s.typed().SetValue(v)
+}
+
+// Separator returns the string used to split text into elements. An
+// empty separator splits text on whitespace.
+func (s *IntSliceSetting) Separator() string {
+	return s.SeparatorValue
+}
+
+func (s *IntSliceSetting) setSeparator(sep string) {
+	s.SeparatorValue = sep
+}
+
+func (s *IntSliceSetting) typed() *TypedSetting[[]int] {
+	return &TypedSetting[[]int]{BaseSetting: s.BaseSetting, TypedValue: s.IntSliceValue, SeparatorValue: s.SeparatorValue}
+}
+
+func (s *IntSliceSetting) detach() Setting {
+	return s.typed().detach()
+}
+
+// Int8SliceSetting manages an instance of []int8.
+type Int8SliceSetting = TypedSetting[[]int8]
+
+// NewInt8SliceSetting creates an Int8SliceSetting with the given default value.
+func NewInt8SliceSetting(name string, description string, fallback []int8) *Int8SliceSetting {
+	return NewTypedSetting(name, description, fallback, nil)
+}
+
+// Int16SliceSetting manages an instance of []int16.
+type Int16SliceSetting = TypedSetting[[]int16]
+
+// NewInt16SliceSetting creates an Int16SliceSetting with the given default value.
+func NewInt16SliceSetting(name string, description string, fallback []int16) *Int16SliceSetting {
+	return NewTypedSetting(name, description, fallback, nil)
+}
+
+// Int32SliceSetting manages an instance of []int32.
+type Int32SliceSetting = TypedSetting[[]int32]
+
+// NewInt32SliceSetting creates an Int32SliceSetting with the given default value.
+func NewInt32SliceSetting(name string, description string, fallback []int32) *Int32SliceSetting {
+	return NewTypedSetting(name, description, fallback, nil)
+}
+
+// Int64SliceSetting manages an instance of []int64.
+type Int64SliceSetting = TypedSetting[[]int64]
+
+// NewInt64SliceSetting creates an Int64SliceSetting with the given default value.
+func NewInt64SliceSetting(name string, description string, fallback []int64) *Int64SliceSetting {
+	return NewTypedSetting(name, description, fallback, nil)
+}
+
+// UintSliceSetting manages an instance of []uint.
+type UintSliceSetting = TypedSetting[[]uint]
+
+// NewUintSliceSetting creates a UintSliceSetting with the given default value.
+func NewUintSliceSetting(name string, description string, fallback []uint) *UintSliceSetting {
+	return NewTypedSetting(name, description, fallback, nil)
+}
+
+// Uint8SliceSetting manages an instance of []uint8, which is also []byte.
+type Uint8SliceSetting = TypedSetting[[]uint8]
+
+// NewUint8SliceSetting creates a Uint8SliceSetting with the given default value.
+func NewUint8SliceSetting(name string, description string, fallback []uint8) *Uint8SliceSetting {
+	return NewTypedSetting(name, description, fallback, nil)
+}
+
+// Uint16SliceSetting manages an instance of []uint16.
+type Uint16SliceSetting = TypedSetting[[]uint16]
+
+// NewUint16SliceSetting creates a Uint16SliceSetting with the given default value.
+func NewUint16SliceSetting(name string, description string, fallback []uint16) *Uint16SliceSetting {
+	return NewTypedSetting(name, description, fallback, nil)
+}
+
+// Uint32SliceSetting manages an instance of []uint32.
+type Uint32SliceSetting = TypedSetting[[]uint32]
+
+// NewUint32SliceSetting creates a Uint32SliceSetting with the given default value.
+func NewUint32SliceSetting(name string, description string, fallback []uint32) *Uint32SliceSetting {
+	return NewTypedSetting(name, description, fallback, nil)
+}
+
+// Uint64SliceSetting manages an instance of []uint64.
+type Uint64SliceSetting = TypedSetting[[]uint64]
+
+// NewUint64SliceSetting creates a Uint64SliceSetting with the given default value.
+func NewUint64SliceSetting(name string, description string, fallback []uint64) *Uint64SliceSetting {
+	return NewTypedSetting(name, description, fallback, nil)
 }
 
-// StringSliceSetting manages an instance of []string
+// Float32SliceSetting manages an instance of []float32.
+type Float32SliceSetting = TypedSetting[[]float32]
+
+// NewFloat32SliceSetting creates a Float32SliceSetting with the given default value.
+func NewFloat32SliceSetting(name string, description string, fallback []float32) *Float32SliceSetting {
+	return NewTypedSetting(name, description, fallback, nil)
+}
+
+// Float64SliceSetting manages an instance of []float64.
+type Float64SliceSetting = TypedSetting[[]float64]
+
+// NewFloat64SliceSetting creates a Float64SliceSetting with the given default value.
+func NewFloat64SliceSetting(name string, description string, fallback []float64) *Float64SliceSetting {
+	return NewTypedSetting(name, description, fallback, nil)
+}
+
+// TimeSliceSetting manages an instance of []time.Time.
+type TimeSliceSetting = TypedSetting[[]time.Time]
+
+// NewTimeSliceSetting creates a TimeSliceSetting with the given default value.
+func NewTimeSliceSetting(name string, description string, fallback []time.Time) *TimeSliceSetting {
+	return NewTypedSetting(name, description, fallback, nil)
+}
+
+// StringSliceSetting manages an instance of []string.
 type StringSliceSetting struct {
 	*BaseSetting
 	StringSliceValue *[]string
+	SeparatorValue   string
 }
 
 // NewStringSliceSetting creates a StringSliceSetting with the given default value.
@@ -662,27 +1355,31 @@ func (s *StringSliceSetting) Value() interface{} {
 
 // SetValue changes the underlying []string.
 func (s *StringSliceSetting) SetValue(v interface{}) error {
-	var err error
-	*s.StringSliceValue, err = cast.ToStringSliceE(v)
-	return err
+	return s.typed().SetValue(v)
 }
 
-// StringMapStringSliceSetting manages an instance of map[string][]string.
-type StringMapStringSliceSetting struct {
-	*BaseSetting
-	StringMapStringSliceValue *map[string][]string
+// Separator returns the string used to split text into elements. An
+// empty separator splits text on whitespace.
+func (s *StringSliceSetting) Separator() string {
+	return s.SeparatorValue
 }
 
-// Value returns the underlying map[string][]string.
-func (m *StringMapStringSliceSetting) Value() interface{} {
-	return *m.StringMapStringSliceValue
+func (s *StringSliceSetting) setSeparator(sep string) {
+	s.SeparatorValue = sep
 }
 
-// SetValue changes the underlying map[string][]string.
-func (m *StringMapStringSliceSetting) SetValue(v interface{}) error {
-	var err error
-	*m.StringMapStringSliceValue, err = cast.ToStringMapStringSliceE(v)
-	return err
+func (s *StringSliceSetting) typed() *TypedSetting[[]string] {
+	return &TypedSetting[[]string]{BaseSetting: s.BaseSetting, TypedValue: s.StringSliceValue, SeparatorValue: s.SeparatorValue}
+}
+
+func (s *StringSliceSetting) detach() Setting {
+	return s.typed().detach()
+}
+
+// StringMapStringSliceSetting manages an instance of map[string][]string.
+type StringMapStringSliceSetting struct {
+	*BaseSetting
+	StringMapStringSliceValue *map[string][]string
 }
 
 // NewStringMapStringSliceSetting creates a StringMapStringSliceSetting with the given default value.
@@ -696,31 +1393,307 @@ func NewStringMapStringSliceSetting(name string, description string, fallback ma
 	}
 }
 
+// Value returns the underlying map[string][]string.
+func (m *StringMapStringSliceSetting) Value() interface{} {
+	return *m.StringMapStringSliceValue
+}
+
+// SetValue changes the underlying map[string][]string.
+func (m *StringMapStringSliceSetting) SetValue(v interface{}) error {
+	return m.typed().SetValue(v)
+}
+
+func (m *StringMapStringSliceSetting) typed() *TypedSetting[map[string][]string] {
+	return &TypedSetting[map[string][]string]{BaseSetting: m.BaseSetting, TypedValue: m.StringMapStringSliceValue}
+}
+
+func (m *StringMapStringSliceSetting) detach() Setting {
+	return m.typed().detach()
+}
+
 // StringMapStringSetting manages an instance of map[string]string.
 type StringMapStringSetting struct {
 	*BaseSetting
 	StringMapStringValue *map[string]string
 }
 
-// Value returns the underlying map[string][]string.
+// NewStringMapStringSetting creates a StringMapStringSetting with the given default value.
+func NewStringMapStringSetting(name string, description string, fallback map[string]string) *StringMapStringSetting {
+	return &StringMapStringSetting{
+		BaseSetting: &BaseSetting{
+			NameValue:        name,
+			DescriptionValue: description,
+		},
+		StringMapStringValue: &fallback,
+	}
+}
+
+// Value returns the underlying map[string]string.
 func (m *StringMapStringSetting) Value() interface{} {
 	return *m.StringMapStringValue
 }
 
-// SetValue changes the underlying map[string][]string.
+// SetValue changes the underlying map[string]string.
 func (m *StringMapStringSetting) SetValue(v interface{}) error {
-	var err error
-	*m.StringMapStringValue, err = cast.ToStringMapStringE(v)
-	return err
+	return m.typed().SetValue(v)
 }
 
-// NewStringMapStringSetting creates a StringMapStringSetting with the given default value.
-func NewStringMapStringSetting(name string, description string, fallback map[string]string) *StringMapStringSetting {
-	return &StringMapStringSetting{
+func (m *StringMapStringSetting) typed() *TypedSetting[map[string]string] {
+	return &TypedSetting[map[string]string]{BaseSetting: m.BaseSetting, TypedValue: m.StringMapStringValue}
+}
+
+func (m *StringMapStringSetting) detach() Setting {
+	return m.typed().detach()
+}
+
+// ByteSizeSetting manages an instance of ByteSize.
+type ByteSizeSetting = TypedSetting[ByteSize]
+
+// NewByteSizeSetting creates a ByteSizeSetting with the given default value.
+func NewByteSizeSetting(name string, description string, fallback ByteSize) *ByteSizeSetting {
+	return NewTypedSetting(name, description, fallback, nil)
+}
+
+// SecretSetting manages an instance of Secret. It is always sensitive.
+type SecretSetting struct {
+	*BaseSetting
+	SecretValue *Secret
+}
+
+// NewSecretSetting creates a SecretSetting with the given default value.
+func NewSecretSetting(name string, description string, fallback Secret) *SecretSetting {
+	return &SecretSetting{
 		BaseSetting: &BaseSetting{
 			NameValue:        name,
 			DescriptionValue: description,
+			SensitiveValue:   true,
 		},
-		StringMapStringValue: &fallback,
+		SecretValue: &fallback,
+	}
+}
+
+// Value returns the underlying Secret.
+func (s *SecretSetting) Value() interface{} {
+	return *s.SecretValue
+}
+
+// SetValue changes the underlying Secret.
+func (s *SecretSetting) SetValue(v interface{}) error {
+	return s.typed().SetValue(v)
+}
+
+// Sensitive always returns true for a secret.
+func (s *SecretSetting) Sensitive() bool {
+	return true
+}
+
+func (s *SecretSetting) typed() *TypedSetting[Secret] {
+	return &TypedSetting[Secret]{BaseSetting: s.BaseSetting, TypedValue: s.SecretValue}
+}
+
+func (s *SecretSetting) detach() Setting {
+	v := *s.SecretValue
+	c := *s
+	c.SecretValue = &v
+	return &c
+}
+
+// TextSetting manages an instance of any type that implements
+// encoding.TextUnmarshaler. Values are converted to text before being
+// given to the UnmarshalText method.
+type TextSetting struct {
+	*BaseSetting
+	TextValue encoding.TextUnmarshaler
+}
+
+// NewTextSetting creates a TextSetting that manages the value referenced by
+// the given pointer. The current content of the value is the default.
+func NewTextSetting(name string, description string, value encoding.TextUnmarshaler) *TextSetting {
+	return &TextSetting{
+		BaseSetting: &BaseSetting{
+			NameValue:        name,
+			DescriptionValue: description,
+		},
+		TextValue: value,
+	}
+}
+
+// detach loads text into a new, empty value rather than a copy because
+// UnmarshalText may reuse memory that a copy would share with the original.
+func (s *TextSetting) detach() Setting {
+	c := *s
+	c.TextValue = reflect.New(reflect.TypeOf(s.TextValue).Elem()).Interface().(encoding.TextUnmarshaler)
+	return &detachedText{TextSetting: &c, original: s}
+}
+
+// detachedText is a copy of a TextSetting that reports the value of the
+// original until a value is loaded.
+type detachedText struct {
+	*TextSetting
+	original *TextSetting
+	set      bool
+}
+
+// Value returns the loaded value or, if none was loaded, the original.
+func (s *detachedText) Value() interface{} {
+	if s.set {
+		return s.TextSetting.Value()
+	}
+	return s.original.Value()
+}
+
+// SetValue unmarshals the given value into the copy.
+func (s *detachedText) SetValue(v interface{}) error {
+	if err := s.TextSetting.SetValue(v); err != nil {
+		return err
+	}
+	s.set = true
+	return nil
+}
+
+// Value returns the value referenced by the underlying pointer.
+func (s *TextSetting) Value() interface{} {
+	return reflect.ValueOf(s.TextValue).Elem().Interface()
+}
+
+// SetValue unmarshals the given value into the underlying pointer.
+func (s *TextSetting) SetValue(v interface{}) error {
+	rv := reflect.ValueOf(s.TextValue).Elem()
+	if v != nil && reflect.TypeOf(v) == rv.Type() {
+		rv.Set(reflect.ValueOf(v))
+		return nil
+	}
+	if b, ok := v.([]byte); ok {
+		return s.TextValue.UnmarshalText(b)
+	}
+	str, err := cast.ToStringE(v)
+	if err != nil {
+		return err
+	}
+	return s.TextValue.UnmarshalText([]byte(str))
+}
+
+// ConverterSetting manages an instance of any type with a registered
+// Converter. ConverterValue must be a pointer to the managed value.
+type ConverterSetting struct {
+	*BaseSetting
+	Converter      Converter
+	ConverterValue interface{}
+}
+
+// NewConverterSetting creates a ConverterSetting that manages the value
+// referenced by the given pointer. The current content of the value is
+// the default.
+func NewConverterSetting(name string, description string, value interface{}, c Converter) *ConverterSetting {
+	return &ConverterSetting{
+		BaseSetting: &BaseSetting{
+			NameValue:        name,
+			DescriptionValue: description,
+		},
+		Converter:      c,
+		ConverterValue: value,
+	}
+}
+
+func (s *ConverterSetting) detach() Setting {
+	c := *s
+	c.ConverterValue = copyPointer(s.ConverterValue)
+	return &c
+}
+
+// Value returns the value referenced by the underlying pointer.
+func (s *ConverterSetting) Value() interface{} {
+	return reflect.ValueOf(s.ConverterValue).Elem().Interface()
+}
+
+// SetValue parses the given value with the Converter and stores the result.
+func (s *ConverterSetting) SetValue(v interface{}) error {
+	out, err := s.Converter.Parse(v)
+	if err != nil {
+		return err
+	}
+	rv := reflect.ValueOf(s.ConverterValue).Elem()
+	ov := reflect.ValueOf(out)
+	if !ov.IsValid() {
+		rv.Set(reflect.Zero(rv.Type()))
+		return nil
+	}
+	if !ov.Type().AssignableTo(rv.Type()) {
+		if !ov.Type().ConvertibleTo(rv.Type()) {
+			return fmt.Errorf("converter produced %s but setting requires %s", ov.Type(), rv.Type())
+		}
+		ov = ov.Convert(rv.Type())
+	}
+	rv.Set(ov)
+	return nil
+}
+
+// MapSetting manages an instance of any map with string keys. Each value
+// in the map is converted using the same rules as an individual setting of
+// the map's value type. MapValue must be a pointer to the managed map.
+type MapSetting struct {
+	*BaseSetting
+	MapValue interface{}
+}
+
+// NewMapSetting creates a MapSetting that manages the map referenced by the
+// given pointer. The current content of the map is the default.
+func NewMapSetting(name string, description string, value interface{}) *MapSetting {
+	return &MapSetting{
+		BaseSetting: &BaseSetting{
+			NameValue:        name,
+			DescriptionValue: description,
+		},
+		MapValue: value,
+	}
+}
+
+func (s *MapSetting) detach() Setting {
+	c := *s
+	c.MapValue = copyPointer(s.MapValue)
+	return &c
+}
+
+// Value returns the map referenced by the underlying pointer.
+func (s *MapSetting) Value() interface{} {
+	return reflect.ValueOf(s.MapValue).Elem().Interface()
+}
+
+// SetValue converts each element of the given value and replaces the map.
+func (s *MapSetting) SetValue(v interface{}) error {
+	rv := reflect.ValueOf(s.MapValue).Elem()
+	if v != nil && reflect.TypeOf(v) == rv.Type() {
+		rv.Set(reflect.ValueOf(v))
+		return nil
+	}
+	raw, err := rawMap(v)
+	if err != nil {
+		return err
+	}
+	out := reflect.MakeMapWithSize(rv.Type(), len(raw))
+	for k, rawElem := range raw {
+		elem := reflect.New(rv.Type().Elem()).Elem()
+		if err := setElement(k, elem, rawElem); err != nil {
+			return fmt.Errorf("failed to load map key %s due to: %s", k, err.Error())
+		}
+		out.SetMapIndex(reflect.ValueOf(k).Convert(rv.Type().Key()), elem)
+	}
+	rv.Set(out)
+	return nil
+}
+
+// setElement loads a raw value into an addressable value of any type
+// that the library can convert. Empty interfaces receive the raw value.
+func setElement(name string, elem reflect.Value, raw interface{}) error {
+	if elem.Kind() == reflect.Interface && elem.NumMethod() == 0 {
+		if raw != nil {
+			elem.Set(reflect.ValueOf(raw))
+		}
+		return nil
+	}
+	set, err := settingFromValue(&BaseSetting{NameValue: name}, elem)
+	if err != nil {
+		return err
 	}
+	return set.SetValue(raw)
 }
@@ -1,7 +1,11 @@
 package settings
 
 import (
+	"context"
+	"errors"
+	"net"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -148,6 +152,24 @@ func TestSetting(t *testing.T) {
 			expected: time.Date(1999, time.January, 1, 0, 0, 0, 0, time.UTC),
 			bad:      "false",
 		},
+		{
+			name:     "Time with layouts",
+			setting:  NewLayoutTimeSetting("Time", "", time.Now(), time.RFC3339, time.DateOnly),
+			good:     "1999-01-01",
+			expected: time.Date(1999, time.January, 1, 0, 0, 0, 0, time.UTC),
+			bad:      "01/01/1999",
+		},
+		{
+			name: "Time with location",
+			setting: func() Setting {
+				s := NewLayoutTimeSetting("Time", "", time.Now(), time.DateTime)
+				s.LocationValue = time.FixedZone("EST", -5*60*60)
+				return s
+			}(),
+			good:     "1999-01-01 12:00:00",
+			expected: time.Date(1999, time.January, 1, 12, 0, 0, 0, time.FixedZone("EST", -5*60*60)),
+			bad:      "1999-01-01",
+		},
 		{
 			name:     "Duration",
 			setting:  NewDurationSetting("Duration", "", 0),
@@ -183,6 +205,129 @@ func TestSetting(t *testing.T) {
 			expected: []string{"one", "two", "three"},
 			bad:      make(map[string]interface{}),
 		},
+		{
+			name: "Typed with custom cast",
+			setting: NewTypedSetting("Typed", "", "", func(v interface{}) (string, error) {
+				s, ok := v.(string)
+				if !ok {
+					return "", errors.New("not a string")
+				}
+				return strings.ToUpper(s), nil
+			}),
+			good:     "loud",
+			expected: "LOUD",
+			bad:      1,
+		},
+		{
+			name:     "Typed without a built-in cast",
+			setting:  NewTypedSetting[struct{}]("Typed", "", struct{}{}, nil),
+			good:     struct{}{},
+			expected: struct{}{},
+			bad:      "anything",
+		},
+		{
+			name:     "Secret",
+			setting:  NewSecretSetting("Secret", "", ""),
+			good:     "hunter2",
+			expected: Secret("hunter2"),
+			bad:      make(map[string]interface{}),
+		},
+		{
+			name:     "Text",
+			setting:  NewTextSetting("Text", "", new(net.IP)),
+			good:     "127.0.0.1",
+			expected: net.IPv4(127, 0, 0, 1),
+			bad:      "not an ip",
+		},
+		{
+			name:     "Text from bytes",
+			setting:  NewTextSetting("Text", "", new(testLevel)),
+			good:     []byte("debug"),
+			expected: testLevel(1),
+			bad:      "trace",
+		},
+		{
+			name:     "Text from same type",
+			setting:  NewTextSetting("Text", "", new(testLevel)),
+			good:     testLevel(1),
+			expected: testLevel(1),
+			bad:      "trace",
+		},
+		{
+			name:     "Int64Slice",
+			setting:  NewInt64SliceSetting("Int64Slice", "", nil),
+			good:     "1 2 3",
+			expected: []int64{1, 2, 3},
+			bad:      "1 false",
+		},
+		{
+			name:     "UintSlice",
+			setting:  NewUintSliceSetting("UintSlice", "", nil),
+			good:     []interface{}{1, "2", 3.0},
+			expected: []uint{1, 2, 3},
+			bad:      "-1",
+		},
+		{
+			name:     "Float32Slice",
+			setting:  NewFloat32SliceSetting("Float32Slice", "", nil),
+			good:     "1.5 2",
+			expected: []float32{1.5, 2},
+			bad:      "false",
+		},
+		{
+			name:     "Float64Slice from JSON",
+			setting:  NewFloat64SliceSetting("Float64Slice", "", nil),
+			good:     []interface{}{1.5, 2.0},
+			expected: []float64{1.5, 2},
+			bad:      []interface{}{"one"},
+		},
+		{
+			name:     "TimeSlice",
+			setting:  NewTimeSliceSetting("TimeSlice", "", nil),
+			good:     []interface{}{"1999-01-01T00:00:00Z", time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)},
+			expected: []time.Time{time.Date(1999, time.January, 1, 0, 0, 0, 0, time.UTC), time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)},
+			bad:      "yesterday",
+		},
+		{
+			name: "StringSlice with separator",
+			setting: func() Setting {
+				s := NewStringSliceSetting("StringSlice", "", nil)
+				s.SeparatorValue = ","
+				return s
+			}(),
+			good:     "one, two words ,three",
+			expected: []string{"one", "two words", "three"},
+			bad:      make(map[string]interface{}),
+		},
+		{
+			name:     "StringSlice from JSON text",
+			setting:  NewStringSliceSetting("StringSlice", "", nil),
+			good:     `["two words", "", "a,b"]`,
+			expected: []string{"two words", "", "a,b"},
+			bad:      `["unterminated`,
+		},
+		{
+			name: "StringSlice from JSON text with separator",
+			setting: func() Setting {
+				s := NewStringSliceSetting("StringSlice", "", nil)
+				s.SeparatorValue = ","
+				return s
+			}(),
+			good:     ` ["a,b", "c"]`,
+			expected: []string{"a,b", "c"},
+			bad:      make(map[string]interface{}),
+		},
+		{
+			name: "DurationSlice with separator",
+			setting: func() Setting {
+				s := NewDurationSliceSetting("DurationSlice", "", nil)
+				s.SeparatorValue = ";"
+				return s
+			}(),
+			good:     "1s;1m",
+			expected: []time.Duration{time.Second, time.Minute},
+			bad:      "1s 1m",
+		},
 		{
 			name:     "StringMapStringSlice from JSON",
 			setting:  NewStringMapStringSliceSetting("StringMapStringSlice", "", nil),
@@ -211,6 +356,48 @@ func TestSetting(t *testing.T) {
 			expected: map[string]string{"fruit": "apple", "vegetable": "corn"},
 			bad:      `- animal - dog`,
 		},
+		{
+			name:     "ByteSize",
+			setting:  NewByteSizeSetting("ByteSize", "", 0),
+			good:     "10MB",
+			expected: 10 * Megabyte,
+			bad:      "10 parsecs",
+		},
+		{
+			name:     "ByteSize from number",
+			setting:  NewByteSizeSetting("ByteSize", "", 0),
+			good:     4096,
+			expected: 4 * Kibibyte,
+			bad:      -1,
+		},
+		{
+			name:     "Map from JSON",
+			setting:  NewMapSetting("Map", "", &map[string]int{}),
+			good:     `{"a": 1, "b": 2}`,
+			expected: map[string]int{"a": 1, "b": 2},
+			bad:      `{"a": "one"}`,
+		},
+		{
+			name:     "Map from pairs",
+			setting:  NewMapSetting("Map", "", &map[string]time.Duration{}),
+			good:     "a=1s,b=1m",
+			expected: map[string]time.Duration{"a": time.Second, "b": time.Minute},
+			bad:      "a",
+		},
+		{
+			name:     "Map from subtree",
+			setting:  NewMapSetting("Map", "", &map[string][]int{}),
+			good:     map[string]interface{}{"a": []interface{}{1, 2}, "b": "3 4"},
+			expected: map[string][]int{"a": {1, 2}, "b": {3, 4}},
+			bad:      map[string]interface{}{"a": true},
+		},
+		{
+			name:     "Map of interfaces",
+			setting:  NewMapSetting("Map", "", &map[string]interface{}{}),
+			good:     map[string]interface{}{"a": 1, "b": "two"},
+			expected: map[string]interface{}{"a": 1, "b": "two"},
+			bad:      "{",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -230,3 +417,29 @@ func TestSetting(t *testing.T) {
 		})
 	}
 }
+
+func TestSetting_fields(t *testing.T) {
+	port := 80
+	tags := []string{}
+	timeout := time.Second
+	s := &SettingGroup{SettingValues: []Setting{
+		&IntSetting{BaseSetting: &BaseSetting{NameValue: "Port"}, IntValue: &port},
+		&StringSliceSetting{BaseSetting: &BaseSetting{NameValue: "Tags"}, StringSliceValue: &tags},
+		&DurationSetting{BaseSetting: &BaseSetting{NameValue: "Timeout"}, DurationValue: &timeout},
+	}}
+	src := NewMapSource(map[string]interface{}{"port": "8080", "tags": "a b", "timeout": "1m"})
+	if err := Load(context.Background(), src, s.Settings()); err != nil {
+		t.Fatal(err)
+	}
+	if port != 8080 || !reflect.DeepEqual(tags, []string{"a", "b"}) || timeout != time.Minute {
+		t.Errorf("Load() = %d %v %s", port, tags, timeout)
+	}
+
+	g, err := Convert(&struct{ Port int }{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if is, ok := g.Settings()[0].(*IntSetting); !ok || *is.IntValue != 0 {
+		t.Errorf("Convert() setting = %#v, want *IntSetting", g.Settings()[0])
+	}
+}
@@ -1,12 +1,15 @@
 package settings
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -79,11 +82,11 @@ func NewMapSource(m map[string]interface{}) *MapSource {
 // Get traverses a configuration map until it finds the requested element
 // or reaches a dead end.  Variable expansion is supported when the value
 // is a string with ${} wrapped around a key.
-func (s *MapSource) Get(_ context.Context, path ...string) (interface{}, bool) {
-	return s.getRecursive(nil, 0, path...)
+func (s *MapSource) Get(ctx context.Context, path ...string) (interface{}, bool) {
+	return s.getRecursive(ctx, nil, 0, path...)
 }
 
-func (s *MapSource) getRecursive(valueAtTopOfRecursionStack interface{}, recursionDepth int, path ...string) (interface{}, bool) {
+func (s *MapSource) getRecursive(ctx context.Context, valueAtTopOfRecursionStack interface{}, recursionDepth int, path ...string) (interface{}, bool) {
 	if recursionDepth > infiniteRecursionDepthLimit {
 		return nil, false
 	}
@@ -110,8 +113,9 @@ func (s *MapSource) getRecursive(valueAtTopOfRecursionStack interface{}, recursi
 		if valueAtTopOfRecursionStack == nil {
 			valueAtTopOfRecursionStack = vString
 		}
+		traceFromContext(ctx).expand(vString)
 		key := unwrap([]byte(vString))
-		subValue, subFound := s.getRecursive(valueAtTopOfRecursionStack, recursionDepth+1, strings.Split(string(key), "_")...)
+		subValue, subFound := s.getRecursive(ctx, valueAtTopOfRecursionStack, recursionDepth+1, strings.Split(string(key), "_")...)
 		if !subFound {
 			return valueAtTopOfRecursionStack, true
 		}
@@ -121,11 +125,65 @@ func (s *MapSource) getRecursive(valueAtTopOfRecursionStack interface{}, recursi
 	return v, ok
 }
 
-// NewJSONSource generates a config source from a JSON string.
+// NewJSONSource generates a config source from a JSON string. Any
+// "$comment" keys, such as the descriptions in the output of
+// ExampleJSONGroups, are removed from every object outside of a list.
 func NewJSONSource(b []byte) (*MapSource, error) {
 	v := make(map[string]interface{})
-	err := json.Unmarshal(b, &v)
-	return NewMapSource(v), err
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	err := dec.Decode(&v)
+	if err == nil {
+		// Decode stops after the first value so anything that follows it
+		// must be rejected separately.
+		if extra := dec.Decode(&json.RawMessage{}); extra != io.EOF {
+			err = fmt.Errorf("unexpected data after the JSON document")
+		}
+	}
+	stripJSONComments(v)
+	return NewMapSource(jsonNumbers(v).(map[string]interface{})), err
+}
+
+// stripJSONComments removes the descriptions that JSON examples hold under
+// jsonCommentKey so that an example can be loaded as configuration.
+func stripJSONComments(m map[string]interface{}) {
+	delete(m, jsonCommentKey)
+	for _, v := range m {
+		if child, ok := v.(map[string]interface{}); ok {
+			stripJSONComments(child)
+		}
+	}
+}
+
+// jsonNumbers replaces the numbers in decoded JSON with float64, matching
+// json.Unmarshal, except for integers that a float64 cannot hold exactly.
+func jsonNumbers(v interface{}) interface{} {
+	switch vv := v.(type) {
+	case map[string]interface{}:
+		for k, e := range vv {
+			vv[k] = jsonNumbers(e)
+		}
+		return vv
+	case []interface{}:
+		for x, e := range vv {
+			vv[x] = jsonNumbers(e)
+		}
+		return vv
+	case json.Number:
+		f, err := vv.Float64()
+		if err == nil && (f > -(1<<53) && f < 1<<53 || strings.ContainsAny(vv.String(), ".eE")) {
+			return f
+		}
+		if i, err := vv.Int64(); err == nil {
+			return i
+		}
+		if u, err := strconv.ParseUint(vv.String(), 10, 64); err == nil {
+			return u
+		}
+		return f
+	default:
+		return v
+	}
 }
 
 // NewYAMLSource generates a config source from a YAML string.
@@ -263,6 +321,28 @@ func (s *PrefixSource) Get(ctx context.Context, path ...string) (interface{}, bo
 	return s.Source.Get(ctx, path...)
 }
 
+// NamedSource attaches a name to another Source. The name is reported by
+// ExplainGroups for every value that the wrapped Source supplies which makes
+// it possible to tell the members of a MultiSource apart.
+type NamedSource struct {
+	NameValue string
+	Source    Source
+}
+
+// Name returns the name of the source.
+func (s *NamedSource) Name() string {
+	return s.NameValue
+}
+
+// Get a value from the wrapped Source.
+func (s *NamedSource) Get(ctx context.Context, path ...string) (interface{}, bool) {
+	v, found := s.Source.Get(ctx, path...)
+	if found {
+		traceFromContext(ctx).source(s.NameValue)
+	}
+	return v, found
+}
+
 // MultiSource is an ordered set of Sources from which to pull
 // values. It will search until the first Source returns a found
 // value or will return false for found.
@@ -294,9 +374,15 @@ func (ms MultiSource) getRecursive(ctx context.Context, valueAtTopOfRecursionSta
 		if valueAtTopOfRecursionStack == nil {
 			valueAtTopOfRecursionStack = vString
 		}
+		// Any source names recorded so far belong to the reference rather
+		// than the expanded value so they are only kept if the expansion fails.
+		trace := traceFromContext(ctx)
+		trace.expand(vString)
+		names := trace.resetSources()
 		key := unwrap([]byte(vString))
 		subValue, subFound := ms.getRecursive(ctx, valueAtTopOfRecursionStack, recursionDepth+1, strings.Split(string(key), "_")...)
 		if !subFound {
+			trace.restoreSources(names)
 			return valueAtTopOfRecursionStack, true
 		}
 		return subValue, subFound
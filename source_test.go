@@ -423,6 +423,47 @@ c:
 	}
 }
 
+func TestNewJSONSource_comments(t *testing.T) {
+	s, err := NewJSONSource([]byte(`{"$comment": ["a"], "a": {"$comment": ["b"], "b": 1, "c": [{"$comment": "kept"}]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"a": map[string]interface{}{"b": float64(1), "c": []interface{}{map[string]interface{}{"$comment": "kept"}}},
+	}
+	if !reflect.DeepEqual(s.Map, want) {
+		t.Errorf("NewJSONSource() = %#v, want %#v", s.Map, want)
+	}
+}
+
+func TestNewJSONSource_trailingData(t *testing.T) {
+	for _, b := range []string{`{"a": 1} garbage`, `{"a": 1} {"b": 2}`, `{"a": 1}]`} {
+		if _, err := NewJSONSource([]byte(b)); err == nil {
+			t.Errorf("NewJSONSource(%q) accepted trailing data", b)
+		}
+	}
+	if _, err := NewJSONSource([]byte("{\"a\": 1}\n\t ")); err != nil {
+		t.Errorf("NewJSONSource() rejected trailing whitespace: %s", err)
+	}
+}
+
+func TestNewJSONSource_numbers(t *testing.T) {
+	s, err := NewJSONSource([]byte(`{"small": 1, "float": 1.5, "big": 9223372036854775807, "bigger": 18446744073709551615, "list": [2, 9007199254740993]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"small":  float64(1),
+		"float":  1.5,
+		"big":    int64(9223372036854775807),
+		"bigger": uint64(18446744073709551615),
+		"list":   []interface{}{float64(2), int64(9007199254740993)},
+	}
+	if !reflect.DeepEqual(s.Map, want) {
+		t.Errorf("NewJSONSource() = %#v, want %#v", s.Map, want)
+	}
+}
+
 func TestPrefixSource(t *testing.T) {
 	s := NewMapSource(map[string]interface{}{
 		"a": map[string]interface{}{
@@ -0,0 +1,186 @@
+package settings
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// UnknownKey describes a path that is present in a source but does not
+// match any Setting or Group in the tree being loaded. Suggestion holds the
+// closest known path when one is similar enough to be a likely typo.
+type UnknownKey struct {
+	Path       []string
+	Suggestion []string
+}
+
+// String renders the key as a dotted path with an optional suggestion.
+func (k UnknownKey) String() string {
+	if len(k.Suggestion) == 0 {
+		return strings.Join(k.Path, ".")
+	}
+	return fmt.Sprintf("%s (did you mean %s?)", strings.Join(k.Path, "."), strings.Join(k.Suggestion, "."))
+}
+
+// UnknownKeysError is returned by strict loading when a source contains
+// keys that are not recognized by the configuration tree.
+type UnknownKeysError struct {
+	Keys []UnknownKey
+}
+
+func (e *UnknownKeysError) Error() string {
+	names := make([]string, 0, len(e.Keys))
+	for _, k := range e.Keys {
+		names = append(names, k.String())
+	}
+	return fmt.Sprintf("unknown configuration keys: %s", strings.Join(names, ", "))
+}
+
+// keyNode is an element of the tree of known configuration paths.
+type keyNode struct {
+	setting  bool
+	children map[string]*keyNode
+}
+
+func (n *keyNode) child(name string) *keyNode {
+	name = strings.ToLower(name)
+	c, ok := n.children[name]
+	if !ok {
+		c = &keyNode{children: make(map[string]*keyNode)}
+		n.children[name] = c
+	}
+	return c
+}
+
+func knownKeys(groups []Group) *keyNode {
+	root := &keyNode{children: make(map[string]*keyNode)}
+	_ = walkGroups(groups, func(path []string, g Group) error {
+		node := root
+		for _, p := range path {
+			node = node.child(p)
+		}
+		for _, s := range g.Settings() {
+			node.child(s.Name()).setting = true
+		}
+		return nil
+	})
+	return root
+}
+
+// UnknownKeys compares the keys present in the given source against the
+// paths of all settings and groups in the given trees. Every key that does
+// not correspond to a known path is returned in sorted order. Values nested
+// under a setting, such as the entries of a map setting, are never reported
+// and an unknown key hides any keys beneath it.
+func UnknownKeys(s *MapSource, groups []Group) []UnknownKey {
+	var unknown []UnknownKey
+	collectUnknownKeys(nil, s.Map, knownKeys(groups), &unknown)
+	sort.Slice(unknown, func(i, j int) bool {
+		return strings.Join(unknown[i].Path, ".") < strings.Join(unknown[j].Path, ".")
+	})
+	return unknown
+}
+
+func collectUnknownKeys(parent []string, m map[string]interface{}, known *keyNode, unknown *[]UnknownKey) {
+	for k, v := range m {
+		path := make([]string, 0, len(parent)+1)
+		path = append(path, parent...)
+		path = append(path, k)
+		node, ok := known.children[strings.ToLower(k)]
+		if !ok {
+			var suggestion []string
+			if name := suggestKey(k, known); name != "" {
+				suggestion = append(append(make([]string, 0, len(path)), parent...), name)
+			}
+			*unknown = append(*unknown, UnknownKey{Path: path, Suggestion: suggestion})
+			continue
+		}
+		if node.setting {
+			continue
+		}
+		if sub, ok := v.(map[string]interface{}); ok {
+			collectUnknownKeys(path, sub, node, unknown)
+		}
+	}
+}
+
+// suggestKey returns the sibling name closest to the given key or an empty
+// string if none are close enough to be considered a typo.
+func suggestKey(key string, known *keyNode) string {
+	key = strings.ToLower(key)
+	limit := len(key) / 3
+	if limit < 1 {
+		limit = 1
+	}
+	best := ""
+	bestDistance := limit + 1
+	for name := range known.children {
+		d := editDistance(key, name)
+		if d < bestDistance || (d == bestDistance && name < best) {
+			best, bestDistance = name, d
+		}
+	}
+	if bestDistance > limit {
+		return ""
+	}
+	return best
+}
+
+// editDistance computes the edit distance between two strings where an
+// insertion, deletion, substitution, or transposition of adjacent
+// characters each count as a single edit.
+func editDistance(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i = i + 1 {
+		for j := 1; j <= len(rb); j = j + 1 {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
+// StrictLoader loads groups like LoadGroups but first checks a set of
+// sources for keys that the groups do not recognize. Only sources with a
+// closed set of keys, such as those built from YAML or JSON files, should
+// be checked because sources like the process environment always contain
+// unrelated values.
+type StrictLoader struct {
+	// Sources are checked for unknown keys before loading.
+	Sources []*MapSource
+	// OnUnknown, if set, is called for each unknown key and loading then
+	// continues as normal. If nil, unknown keys cause loading to fail with
+	// an *UnknownKeysError before any values are set.
+	OnUnknown func(UnknownKey)
+}
+
+// LoadGroups checks the configured sources for unknown keys and then loads
+// the groups from the given source.
+func (l *StrictLoader) LoadGroups(ctx context.Context, s Source, groups []Group) error {
+	var unknown []UnknownKey
+	for _, src := range l.Sources {
+		unknown = append(unknown, UnknownKeys(src, groups)...)
+	}
+	if len(unknown) > 0 && l.OnUnknown == nil {
+		return &UnknownKeysError{Keys: unknown}
+	}
+	for _, k := range unknown {
+		l.OnUnknown(k)
+	}
+	return LoadGroups(ctx, s, groups)
+}
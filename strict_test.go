@@ -0,0 +1,160 @@
+package settings
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type strictInner struct {
+	MaxConns int
+}
+
+func (*strictInner) Name() string {
+	return "postgres"
+}
+
+type strictConf struct {
+	Host     string
+	Labels   map[string]string
+	Postgres *strictInner
+}
+
+func TestUnknownKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want []UnknownKey
+	}{
+		{
+			name: "all known",
+			yaml: `
+strictconf:
+  host: "localhost"
+  labels:
+    anything: "goes"
+  postgres:
+    maxconns: 3
+`,
+			want: nil,
+		},
+		{
+			name: "typos",
+			yaml: `
+strictconf:
+  hots: "localhost"
+  postgress:
+    maxconns: 3
+`,
+			want: []UnknownKey{
+				{Path: []string{"strictconf", "hots"}, Suggestion: []string{"strictconf", "host"}},
+				{Path: []string{"strictconf", "postgress"}, Suggestion: []string{"strictconf", "postgres"}},
+			},
+		},
+		{
+			name: "nested typo",
+			yaml: `
+strictconf:
+  postgres:
+    maxconn: 3
+`,
+			want: []UnknownKey{
+				{Path: []string{"strictconf", "postgres", "maxconn"}, Suggestion: []string{"strictconf", "postgres", "maxconns"}},
+			},
+		},
+		{
+			name: "comment",
+			yaml: `
+strictconf:
+  $comment: "only removed from JSON sources"
+`,
+			want: []UnknownKey{
+				{Path: []string{"strictconf", "$comment"}},
+			},
+		},
+		{
+			name: "no suggestion",
+			yaml: `
+other: true
+`,
+			want: []UnknownKey{
+				{Path: []string{"other"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := Convert(&strictConf{Postgres: &strictInner{}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			s, err := NewYAMLSource([]byte(tt.yaml))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := UnknownKeys(s, []Group{g}); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UnknownKeys() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStrictLoader_LoadGroups(t *testing.T) {
+	s, err := NewYAMLSource([]byte(`
+strictconf:
+  host: "localhost"
+  postgress:
+    maxconns: 3
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conf := &strictConf{Postgres: &strictInner{}}
+	g, _ := Convert(conf)
+	err = (&StrictLoader{Sources: []*MapSource{s}}).LoadGroups(context.Background(), s, []Group{g})
+	var unknownErr *UnknownKeysError
+	if !errors.As(err, &unknownErr) {
+		t.Fatalf("LoadGroups() error = %v, want *UnknownKeysError", err)
+	}
+	if want := "unknown configuration keys: strictconf.postgress (did you mean strictconf.postgres?)"; err.Error() != want {
+		t.Errorf("LoadGroups() error = %q, want %q", err.Error(), want)
+	}
+	if conf.Host != "" {
+		t.Errorf("LoadGroups() loaded values despite unknown keys")
+	}
+
+	var warned []UnknownKey
+	loader := &StrictLoader{
+		Sources:   []*MapSource{s},
+		OnUnknown: func(k UnknownKey) { warned = append(warned, k) },
+	}
+	if err = loader.LoadGroups(context.Background(), s, []Group{g}); err != nil {
+		t.Fatalf("LoadGroups() error = %v", err)
+	}
+	if len(warned) != 1 {
+		t.Errorf("LoadGroups() warned %d times, want 1", len(warned))
+	}
+	if conf.Host != "localhost" {
+		t.Errorf("LoadGroups() Host = %q, want localhost", conf.Host)
+	}
+}
+
+func Test_editDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"postgres", "postgress", 1},
+		{"kitten", "sitting", 3},
+		{"host", "hots", 1},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
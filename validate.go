@@ -0,0 +1,154 @@
+package settings
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// detacher is implemented by settings that can copy themselves into new
+// storage. Loading a value into the copy leaves the original setting, and
+// the value it manages, unchanged.
+type detacher interface {
+	detach() Setting
+}
+
+// detachSetting returns a copy of the setting that can be loaded without
+// changing the original. Settings of types outside of this package cannot
+// be copied so they are replaced by a setting that records the raw value
+// of the source without converting it.
+func detachSetting(s Setting) Setting {
+	if d, ok := s.(detacher); ok {
+		return d.detach()
+	}
+	return &rawSetting{Setting: s}
+}
+
+// rawSetting records the raw value given to SetValue in place of a setting
+// that cannot be copied. Until a value is set it reports the value of the
+// original setting.
+type rawSetting struct {
+	Setting
+	raw interface{}
+	set bool
+}
+
+// Value returns the raw value, if one was set, or the original value.
+func (s *rawSetting) Value() interface{} {
+	if s.set {
+		return s.raw
+	}
+	return s.Setting.Value()
+}
+
+// SetValue records the raw value without converting it.
+func (s *rawSetting) SetValue(v interface{}) error {
+	s.raw, s.set = v, true
+	return nil
+}
+
+// detachGroups copies the given groups and every setting within them with
+// detachSetting. A PluginGroup remains a PluginGroup so that its sub-trees
+// follow the type loaded into the copy. Any other group is copied into a
+// SettingGroup with the same name, description, settings, and sub-trees.
+func detachGroups(groups []Group) []Group {
+	result := make([]Group, 0, len(groups))
+	for _, g := range groups {
+		result = append(result, detachGroup(g))
+	}
+	return result
+}
+
+func detachGroup(g Group) Group {
+	if pg, ok := g.(*PluginGroup); ok {
+		return pg.detach()
+	}
+	settings := make([]Setting, 0, len(g.Settings()))
+	for _, s := range g.Settings() {
+		settings = append(settings, detachSetting(s))
+	}
+	return &SettingGroup{
+		NameValue:        g.Name(),
+		DescriptionValue: g.Description(),
+		GroupValues:      detachGroups(g.Groups()),
+		SettingValues:    settings,
+	}
+}
+
+// copyPointer returns a new pointer to a copy of the value referenced by
+// the given pointer.
+func copyPointer(p interface{}) interface{} {
+	v := reflect.ValueOf(p).Elem()
+	c := reflect.New(v.Type())
+	c.Elem().Set(v)
+	return c.Interface()
+}
+
+// ValidationProblem describes a single problem found by ValidateSource.
+type ValidationProblem struct {
+	// Path is the full path of the setting or key including all group names.
+	Path    []string
+	Message string
+}
+
+// String renders the problem as a single line of text.
+func (p ValidationProblem) String() string {
+	return fmt.Sprintf("%s: %s", strings.Join(p.Path, "."), p.Message)
+}
+
+// ValidationError is returned by ValidateSource when any problem is found.
+type ValidationError struct {
+	Problems []ValidationProblem
+}
+
+func (e *ValidationError) Error() string {
+	problems := make([]string, 0, len(e.Problems))
+	for _, p := range e.Problems {
+		problems = append(problems, p.String())
+	}
+	return fmt.Sprintf("invalid configuration: %s", strings.Join(problems, "; "))
+}
+
+// ValidateSource checks that the given source can be loaded into the given
+// groups without changing them. Every setting is loaded exactly as it would
+// be by LoadGroups except that values are loaded into copies of the groups,
+// so the given groups are never modified, no component is created, and no
+// Builder is called. Settings of types outside of this package cannot be
+// copied and are only checked for a required value. Rather than stopping
+// at the first problem, every value that cannot be converted and every
+// required setting without a value is reported in a *ValidationError. Any
+// keys of the given strict sources that do not match a setting or group are
+// also reported. Like StrictLoader.Sources, these should only be sources
+// with a closed set of keys, such as files, and never the environment.
+func ValidateSource(ctx context.Context, s Source, groups []Group, strict ...*MapSource) error {
+	var problems []ValidationProblem
+	detached := detachGroups(groups)
+	_, _ = loadWalk(ctx, s, detached, func(_ Group, l settingLoad) error {
+		if l.err != nil {
+			problems = append(problems, ValidationProblem{Path: l.path, Message: l.err.Error()})
+		}
+		return nil
+	})
+	for _, ms := range strict {
+		problems = append(problems, unknownKeyProblems(ms, detached)...)
+	}
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// unknownKeyProblems reports every key of the source that does not match a
+// setting or group.
+func unknownKeyProblems(s *MapSource, groups []Group) []ValidationProblem {
+	var problems []ValidationProblem
+	for _, k := range UnknownKeys(s, groups) {
+		message := "unknown key"
+		if len(k.Suggestion) > 0 {
+			message = fmt.Sprintf("unknown key (did you mean %s?)", strings.Join(k.Suggestion, "."))
+		}
+		problems = append(problems, ValidationProblem{Path: k.Path, Message: message})
+	}
+	return problems
+}
@@ -0,0 +1,165 @@
+package settings
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type validateConf struct {
+	Host     string `required:"true"`
+	Port     int    `required:"true"`
+	Timeout  time.Duration
+	Limit    *int
+	Retries  Optional[int]
+	Labels   map[string]int
+	Password Secret
+}
+
+func validateDefaults() *validateConf {
+	return &validateConf{
+		Host:     "localhost",
+		Timeout:  time.Second,
+		Retries:  NewOptional(3),
+		Labels:   map[string]int{"a": 1},
+		Password: "hunter2",
+	}
+}
+
+func TestValidateSource(t *testing.T) {
+	conf := validateDefaults()
+	g, err := Convert(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewMapSource(map[string]interface{}{
+		"validateconf": map[string]interface{}{
+			"host":     "example.com",
+			"timeout":  "forever",
+			"limit":    "10",
+			"retries":  5,
+			"labels":   map[string]interface{}{"b": "two"},
+			"password": "changed",
+			"hots":     "typo",
+		},
+	})
+	err = ValidateSource(context.Background(), s, []Group{g}, s)
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("ValidateSource() error = %v, want *ValidationError", err)
+	}
+	want := []string{
+		"validateConf.Labels: failed to load map key b due to: unable to cast \"two\" of type string to int64",
+		"validateConf.Timeout: time: invalid duration \"foreverns\"",
+		"validateConf.Port: a value is required",
+		"validateconf.hots: unknown key (did you mean validateconf.host?)",
+	}
+	got := make([]string, 0, len(ve.Problems))
+	for _, p := range ve.Problems {
+		got = append(got, p.String())
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidateSource() problems = %q, want %q", got, want)
+	}
+	if !reflect.DeepEqual(conf, validateDefaults()) {
+		t.Errorf("ValidateSource() changed the settings: %#v", conf)
+	}
+}
+
+func TestValidateSource_valid(t *testing.T) {
+	conf := validateDefaults()
+	g, err := Convert(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &NamedSource{NameValue: "file", Source: NewMapSource(map[string]interface{}{
+		"validateconf": map[string]interface{}{"host": "example.com", "port": 80, "limit": 1},
+	})}
+	if err := ValidateSource(context.Background(), s, []Group{g}); err != nil {
+		t.Fatal(err)
+	}
+	if conf.Host != "localhost" || conf.Port != 0 || conf.Limit != nil {
+		t.Errorf("ValidateSource() changed the settings: %#v", conf)
+	}
+}
+
+func TestValidateSource_env(t *testing.T) {
+	g, err := Convert(validateDefaults())
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := NewEnvSource([]string{"HOME=/root", "PATH=/bin", "VALIDATECONF_HOST=example.com", "VALIDATECONF_PORT=80"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateSource(context.Background(), env, []Group{g}); err != nil {
+		t.Errorf("ValidateSource() error = %v, want unrelated variables ignored", err)
+	}
+}
+
+func TestLoadGroups_required(t *testing.T) {
+	g, err := Convert(validateDefaults())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = LoadGroups(context.Background(), NewMapSource(map[string]interface{}{}), []Group{g})
+	if err == nil {
+		t.Fatal("LoadGroups() accepted a missing required value")
+	}
+}
+
+func TestValidateSource_plugin(t *testing.T) {
+	registerTestCaches(t)
+	conf := &pluginConf{}
+	g, err := Convert(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewMapSource(map[string]interface{}{"pluginconf": map[string]interface{}{
+		"cache": map[string]interface{}{"type": "memory", "memory": map[string]interface{}{"size": "many"}},
+	}})
+	err = ValidateSource(context.Background(), s, []Group{g})
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("ValidateSource() error = %v, want *ValidationError", err)
+	}
+	want := []string{"pluginConf.Cache.memory.Size: unable to cast \"many\" of type string to int64"}
+	got := make([]string, 0, len(ve.Problems))
+	for _, p := range ve.Problems {
+		got = append(got, p.String())
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidateSource() problems = %q, want %q", got, want)
+	}
+	if selected := g.Groups()[0].(*PluginGroup).Selected(); selected != "" || conf.Cache != nil {
+		t.Errorf("ValidateSource() selected %q in the original group", selected)
+	}
+}
+
+func TestValidateSource_concurrent(t *testing.T) {
+	conf := validateDefaults()
+	g, err := Convert(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewMapSource(map[string]interface{}{
+		"validateconf": map[string]interface{}{"host": "example.com", "port": 80, "limit": 1, "retries": 5},
+	})
+	var wg sync.WaitGroup
+	for x := 0; x < 4; x = x + 1 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := ValidateSource(context.Background(), s, []Group{g}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if !reflect.DeepEqual(conf, validateDefaults()) {
+		t.Errorf("ValidateSource() changed the settings: %#v", conf)
+	}
+}